@@ -5,23 +5,40 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port           string
-	Environment    string
-	DBHost         string
-	DBPort         string
-	DBUser         string
-	DBPassword     string
-	DBName         string
-	JWTSecret      string
-	UploadDir      string
-	MaxUploadSize  int64
-	AllowedOrigins []string
-	FrontendURL    string
+	Port            string
+	Environment     string
+	DBHost          string
+	DBPort          string
+	DBUser          string
+	DBPassword      string
+	DBName          string
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	MFATokenTTL     time.Duration
+	TOTPIssuer      string
+	RequireAdmin2FA bool
+	// Brute-force protection
+	OTPMaxAttempts             int
+	OTPResendCooldown          time.Duration
+	LoginMaxFailuresPerAccount int
+	LoginMaxFailuresPerIP      int
+	LoginFailureWindow         time.Duration
+	LoginLockoutBase           time.Duration
+	LoginLockoutMax            time.Duration
+	// Rate limiting
+	RateLimitEnabled bool
+	RateLimits       map[string]RateLimitPolicy // Keyed by route group policy name
+	UploadDir        string
+	MaxUploadSize    int64
+	AllowedOrigins   []string
+	FrontendURL      string
 	// Cloudinary
 	CloudinaryCloudName    string
 	CloudinaryAPIKey       string
@@ -34,6 +51,25 @@ type Config struct {
 	B2S3Region      string
 	B2S3BucketGIF   string
 	B2S3BucketVideo string
+	// Moderation
+	RejectionReasons   []string      // Canned reasons moderators pick from when rejecting
+	ModerationClaimTTL time.Duration // How long a moderator's claim on a queue item lasts
+	ReportThreshold    int           // Open reports that automatically unpublish a prompt
+
+	// Engagement
+	ViewDedupWindow      time.Duration // A viewer's repeat views or downloads of a prompt within this window count once
+	CounterFlushInterval time.Duration // How often buffered view and download counts are written to the database
+
+	// Trending
+	TrendingInterval       time.Duration // How often trending scores and community ranks are recomputed
+	TrendingHalfLifeDaily  time.Duration // Age at which engagement counts half in the daily window
+	TrendingHalfLifeWeekly time.Duration // Age at which engagement counts half in the weekly window
+}
+
+// RateLimitPolicy allows Requests per Per window, refilled continuously (token bucket)
+type RateLimitPolicy struct {
+	Requests int
+	Per      time.Duration
 }
 
 var AppConfig *Config
@@ -48,14 +84,36 @@ func LoadConfig() {
 	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "104857600"), 10, 64)
 
 	AppConfig = &Config{
-		Port:           getEnv("PORT", "8080"),
-		Environment:    getEnv("ENV", "development"),
-		DBHost:         getEnv("DB_HOST", "localhost"),
-		DBPort:         getEnv("DB_PORT", "3306"),
-		DBUser:         getEnv("DB_USER", "root"),
-		DBPassword:     getEnv("DB_PASSWORD", ""),
-		DBName:         getEnv("DB_NAME", "ai_of_the_world"),
-		JWTSecret:      getEnv("JWT_SECRET", "default-secret-change-this"),
+		Port:            getEnv("PORT", "8080"),
+		Environment:     getEnv("ENV", "development"),
+		DBHost:          getEnv("DB_HOST", "localhost"),
+		DBPort:          getEnv("DB_PORT", "3306"),
+		DBUser:          getEnv("DB_USER", "root"),
+		DBPassword:      getEnv("DB_PASSWORD", ""),
+		DBName:          getEnv("DB_NAME", "ai_of_the_world"),
+		JWTSecret:       getEnv("JWT_SECRET", "default-secret-change-this"),
+		AccessTokenTTL:  getDurationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDurationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		MFATokenTTL:     getDurationEnv("MFA_TOKEN_TTL", 5*time.Minute),
+		TOTPIssuer:      getEnv("TOTP_ISSUER", "AI of the World"),
+		RequireAdmin2FA: getEnv("REQUIRE_ADMIN_2FA", "false") == "true",
+		// Brute-force protection
+		OTPMaxAttempts:             getIntEnv("OTP_MAX_ATTEMPTS", 5),
+		OTPResendCooldown:          getDurationEnv("OTP_RESEND_COOLDOWN", time.Minute),
+		LoginMaxFailuresPerAccount: getIntEnv("LOGIN_MAX_FAILURES_PER_ACCOUNT", 5),
+		LoginMaxFailuresPerIP:      getIntEnv("LOGIN_MAX_FAILURES_PER_IP", 20),
+		LoginFailureWindow:         getDurationEnv("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		LoginLockoutBase:           getDurationEnv("LOGIN_LOCKOUT_BASE", time.Minute),
+		LoginLockoutMax:            getDurationEnv("LOGIN_LOCKOUT_MAX", time.Hour),
+		// Rate limiting
+		RateLimitEnabled: getEnv("RATE_LIMIT_ENABLED", "true") == "true",
+		RateLimits: map[string]RateLimitPolicy{
+			"api":     getRateLimitEnv("RATE_LIMIT_API", "300/1m"),
+			"auth":    getRateLimitEnv("RATE_LIMIT_AUTH", "20/1m"),
+			"otp":     getRateLimitEnv("RATE_LIMIT_OTP", "5/10m"),
+			"upload":  getRateLimitEnv("RATE_LIMIT_UPLOAD", "30/1h"),
+			"comment": getRateLimitEnv("RATE_LIMIT_COMMENT", "20/5m"),
+		},
 		UploadDir:      getEnv("UPLOAD_DIR", "./uploads"),
 		MaxUploadSize:  maxUploadSize,
 		AllowedOrigins: strings.Split(getEnv("ALLOWED_ORIGINS", "http://localhost:3000"), ","),
@@ -72,6 +130,19 @@ func LoadConfig() {
 		B2S3Region:      getEnv("B2_S3_REGION", "us-east-005"),
 		B2S3BucketGIF:   getEnv("B2_S3_BUCKET_GIF", "aiofhtheworlsgif"),
 		B2S3BucketVideo: getEnv("B2_S3_BUCKET_VIDEO", "aiofhtheworlsvideo"),
+		// Moderation
+		RejectionReasons:   getListEnv("REJECTION_REASONS", "Low quality or unclear media;Prompt missing or incomplete;Inappropriate or unsafe content;Duplicate submission;Copyright or attribution issue;Off-topic for the community;Other"),
+		ModerationClaimTTL: getDurationEnv("MODERATION_CLAIM_TTL", 15*time.Minute),
+		ReportThreshold:    getIntEnv("REPORT_UNPUBLISH_THRESHOLD", 5),
+
+		// Engagement
+		ViewDedupWindow:      getDurationEnv("VIEW_DEDUP_WINDOW", 30*time.Minute),
+		CounterFlushInterval: getDurationEnv("COUNTER_FLUSH_INTERVAL", 10*time.Second),
+
+		// Trending
+		TrendingInterval:       getDurationEnv("TRENDING_INTERVAL", 15*time.Minute),
+		TrendingHalfLifeDaily:  getDurationEnv("TRENDING_HALF_LIFE_DAILY", 6*time.Hour),
+		TrendingHalfLifeWeekly: getDurationEnv("TRENDING_HALF_LIFE_WEEKLY", 48*time.Hour),
 	}
 
 	log.Println("✅ Configuration loaded successfully")
@@ -83,3 +154,60 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("⚠️  Invalid integer for %s, using default %d\n", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("⚠️  Invalid duration for %s, using default %s\n", key, defaultValue)
+	}
+	return defaultValue
+}
+
+// getListEnv splits a semicolon-separated list, dropping empty entries
+func getListEnv(key, defaultValue string) []string {
+	var items []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getRateLimitEnv parses a policy written as "<requests>/<duration>", e.g. "20/1m"
+func getRateLimitEnv(key, defaultValue string) RateLimitPolicy {
+	if policy, ok := parseRateLimit(getEnv(key, defaultValue)); ok {
+		return policy
+	}
+	log.Printf("⚠️  Invalid rate limit for %s, using default %s\n", key, defaultValue)
+	policy, _ := parseRateLimit(defaultValue)
+	return policy
+}
+
+func parseRateLimit(value string) (RateLimitPolicy, bool) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return RateLimitPolicy{}, false
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || requests < 1 {
+		return RateLimitPolicy{}, false
+	}
+	per, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || per <= 0 {
+		return RateLimitPolicy{}, false
+	}
+	return RateLimitPolicy{Requests: requests, Per: per}, true
+}
@@ -2,8 +2,10 @@ package config
 
 import (
 	"ai-of-the-world-backend/models"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"gorm.io/driver/mysql"
@@ -46,6 +48,26 @@ func ConnectDatabase() {
 	if err := DB.AutoMigrate(
 		&models.User{},
 		&models.OTP{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.AuthThrottle{},
+		&models.APIKey{},
+		&models.AuditEvent{},
+		&models.ModerationNote{},
+		&models.ModerationEvent{},
+		&models.ModerationClaim{},
+		&models.Report{},
+		&models.Like{},
+		&models.EngagementDay{},
+		&models.TrendingScore{},
+		&models.CreatorScore{},
+		&models.Comment{},
+		&models.CommentMention{},
+		&models.Collection{},
+		&models.CollectionItem{},
+		&models.CollectionEditor{},
+		&models.Follow{},
 		&models.Tag{},
 		&models.ImagePrompt{},
 		&models.GIFPrompt{},
@@ -56,6 +78,15 @@ func ConnectDatabase() {
 		log.Println("✅ Database tables migrated successfully")
 	}
 
+	migrateLegacyInterests()
+
+	// Prompts published before published_at existed are dated by their last update
+	for _, table := range []string{"image_prompts", "gif_prompts", "video_prompts"} {
+		if err := DB.Exec("UPDATE "+table+" SET published_at = updated_at WHERE is_published = ? AND published_at IS NULL", true).Error; err != nil {
+			log.Println("⚠️  Failed to backfill published_at:", err)
+		}
+	}
+
 	log.Println("✅ Database connected successfully")
 }
 
@@ -74,3 +105,56 @@ func CloseDatabase() {
 
 	log.Println("✅ Database connection closed")
 }
+
+// migrateLegacyInterests links the tag IDs that used to be stored as a JSON array in
+// users.interests and clears the legacy value, so interests a user later removes
+// aren't linked again on the next start. Users who already have linked interests
+// keep them.
+func migrateLegacyInterests() {
+	if !DB.Migrator().HasColumn("users", "interests") {
+		return
+	}
+
+	var rows []struct {
+		ID        uint
+		Interests string
+	}
+	if err := DB.Table("users").Select("id, interests").
+		Where("interests IS NOT NULL AND interests <> ''").
+		Scan(&rows).Error; err != nil {
+		log.Println("⚠️  Failed to read legacy interests:", err)
+		return
+	}
+
+	for _, row := range rows {
+		var tagIDs []uint64
+		var values []interface{}
+		if err := json.Unmarshal([]byte(row.Interests), &values); err == nil {
+			for _, value := range values {
+				var id uint64
+				switch v := value.(type) {
+				case float64:
+					id = uint64(v)
+				case string:
+					id, _ = strconv.ParseUint(v, 10, 64)
+				}
+				if id > 0 {
+					tagIDs = append(tagIDs, id)
+				}
+			}
+		}
+
+		if err := DB.Transaction(func(tx *gorm.DB) error {
+			if len(tagIDs) > 0 {
+				if err := tx.Exec("INSERT IGNORE INTO user_interests (user_id, tag_id) SELECT ?, id FROM tags WHERE id IN ? "+
+					"AND NOT EXISTS (SELECT 1 FROM user_interests WHERE user_id = ?)", row.ID, tagIDs, row.ID).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Table("users").Where("id = ?", row.ID).UpdateColumn("interests", nil).Error
+		}); err != nil {
+			log.Println("⚠️  Failed to migrate legacy interests:", err)
+			return
+		}
+	}
+}
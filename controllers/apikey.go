@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyAPIKeys lists the current user's active API keys
+func GetMyAPIKeys(c *gin.Context) {
+	userID, _ := currentUser(c)
+
+	keys, err := services.ListAPIKeys(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch API keys")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API keys retrieved successfully", keys)
+}
+
+// CreateAPIKey issues a new API key; the plain key is only returned here
+func CreateAPIKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, _ := currentUser(c)
+
+	raw, key, err := services.CreateAPIKey(userID, req.Name, req.Scopes)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyAPIKeys) {
+			utils.ErrorResponse(c, http.StatusConflict, "API key limit reached. Revoke an unused key first")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "API key created successfully. Store it now; it won't be shown again", models.CreateAPIKeyResponse{
+		Key:    raw,
+		APIKey: *key,
+	})
+}
+
+// RevokeAPIKey revokes one of the current user's API keys
+func RevokeAPIKey(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "API key not found")
+		return
+	}
+
+	userID, _ := currentUser(c)
+
+	if err := services.RevokeAPIKey(userID, id); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "API key not found")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke API key")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked successfully", nil)
+}
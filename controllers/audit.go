@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseTimeQuery parses an optional RFC 3339 timestamp or YYYY-MM-DD date query parameter
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, errors.New("invalid " + name + ": use RFC 3339 or YYYY-MM-DD")
+	}
+	return &t, nil
+}
+
+// GetAuditEvents returns a page of audit events matching the query filters (Admin only)
+func GetAuditEvents(c *gin.Context) {
+	page, err := utils.ParsePageRequest(c, services.AuditSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := services.AuditFilter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		RequestID:  c.Query("request_id"),
+	}
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, pagination, err := services.ListAuditEvents(filter, page)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch audit events")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Audit events retrieved successfully", events, pagination)
+}
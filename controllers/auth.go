@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
 	"ai-of-the-world-backend/config"
 	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
 	"ai-of-the-world-backend/utils"
 
 	"github.com/gin-gonic/gin"
@@ -45,7 +48,7 @@ func Register(c *gin.Context) {
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
 		FullName:     req.FullName,
-		Role:         "user",
+		Role:         models.RoleUser,
 		IsActive:     true,
 	}
 
@@ -54,8 +57,8 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Email, user.Role)
+	// Start a session and issue tokens
+	tokens, err := services.StartSession(&user, sessionClient(c), false)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
 		return
@@ -65,8 +68,8 @@ func Register(c *gin.Context) {
 	user.PasswordHash = ""
 
 	utils.SuccessResponse(c, http.StatusCreated, "User registered successfully", models.AuthResponse{
-		Token: token,
-		User:  user,
+		AuthTokens: *tokens,
+		User:       user,
 	})
 }
 
@@ -78,9 +81,15 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// Refuse attempts while the account or IP is locked out
+	if loginLockedOut(c, req.Email) {
+		return
+	}
+
 	// Find user by email
 	var user models.User
 	if err := config.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		recordLoginFailure(c, req.Email)
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
@@ -93,17 +102,39 @@ func Login(c *gin.Context) {
 
 	// Verify password
 	if !utils.CheckPassword(user.PasswordHash, req.Password) {
+		recordLoginFailure(c, req.Email)
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
+	// A correct password clears the account's failure count
+	if err := services.ResetFailures(services.LoginAccountKey(req.Email)); err != nil {
+		log.Println("⚠️  Failed to reset login failures:", err)
+	}
+
+	// Accounts with two-factor authentication get a challenge instead of tokens
+	if user.TOTPEnabled {
+		mfaToken, expiresAt, err := utils.GenerateMFAToken(user.ID, config.AppConfig.MFATokenTTL)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", models.MFAChallengeResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+			ExpiresAt:   expiresAt,
+		})
+		return
+	}
+
 	// Update last login
 	now := time.Now()
 	user.LastLogin = &now
-	config.DB.Save(&user)
+	config.DB.Model(&user).Update("last_login", now)
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Email, user.Role)
+	// Start a session and issue tokens
+	tokens, err := services.StartSession(&user, sessionClient(c), false)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
 		return
@@ -113,43 +144,124 @@ func Login(c *gin.Context) {
 	user.PasswordHash = ""
 
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", models.AuthResponse{
-		Token: token,
-		User:  user,
+		AuthTokens: *tokens,
+		User:       user,
 	})
 }
 
+// loginLockedOut responds with 429 when the account or client IP is locked out
+func loginLockedOut(c *gin.Context, email string) bool {
+	retryAfter, err := services.LockedFor(services.LoginAccountKey(email), services.LoginIPKey(c.ClientIP()))
+	if err != nil {
+		log.Println("⚠️  Failed to check login lockout:", err)
+		return false
+	}
+	if retryAfter > 0 {
+		utils.TooManyRequestsResponse(c, retryAfter, "Too many failed login attempts. Please try again later")
+		return true
+	}
+	return false
+}
+
+// recordLoginFailure counts a failed login against the account and the client IP
+func recordLoginFailure(c *gin.Context, email string) {
+	if err := services.RecordFailure(services.LoginAccountKey(email), services.LoginAccountPolicy()); err != nil {
+		log.Println("⚠️  Failed to record login failure:", err)
+	}
+	if err := services.RecordFailure(services.LoginIPKey(c.ClientIP()), services.LoginIPPolicy()); err != nil {
+		log.Println("⚠️  Failed to record login failure:", err)
+	}
+}
+
+// RefreshToken rotates a refresh token and issues a new token pair
+func RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, user, err := services.RefreshSession(req.RefreshToken, sessionClient(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken),
+			errors.Is(err, services.ErrRefreshTokenReuse),
+			errors.Is(err, services.ErrSessionRevoked):
+			utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to refresh token")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", models.AuthResponse{
+		AuthTokens: *tokens,
+		User:       *user,
+	})
+}
+
+// Logout revokes the current session
+func Logout(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	sessionID, _ := c.Get("sessionID")
+
+	if err := services.RevokeSession(userID.(uint), sessionID.(uint)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to log out")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
 // GetProfile returns the current user's profile
 func GetProfile(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	var user models.User
-	if err := config.DB.First(&user, userID).Error; err != nil {
+	if err := config.DB.Preload("Interests").First(&user, userID).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
 	}
 
 	user.PasswordHash = ""
-	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", user)
+	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", models.ProfileResponse{
+		User:        user,
+		TOTPEnabled: user.TOTPEnabled,
+	})
 }
 
-// UpdateInterests updates the user's interests
-func UpdateInterests(c *gin.Context) {
-	userID, _ := c.Get("userID")
+// GetInterests returns the tags the user picked as interests
+func GetInterests(c *gin.Context) {
+	userID, _ := currentUser(c)
 
-	var req struct {
-		Interests string `json:"interests" binding:"required"`
+	tags, err := services.GetInterests(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch interests")
+		return
 	}
 
+	utils.SuccessResponse(c, http.StatusOK, "Interests retrieved successfully", tags)
+}
+
+// UpdateInterests replaces the user's interests with a list of active tag IDs
+func UpdateInterests(c *gin.Context) {
+	userID, _ := currentUser(c)
+
+	var req models.UpdateInterestsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Update user interests
-	if err := config.DB.Model(&models.User{}).Where("id = ?", userID).Update("interests", req.Interests).Error; err != nil {
+	tags, err := services.SetInterests(userID, req.TagIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownTags) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Interests must be active tags; "+err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update interests")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Interests updated successfully", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Interests updated successfully", tags)
 }
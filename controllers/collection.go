@@ -0,0 +1,325 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondCollectionError maps a collection service error to an HTTP error response
+func respondCollectionError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Collection not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, "You don't have permission to "+action+" this collection")
+	case errors.Is(err, services.ErrAlreadyCollected):
+		utils.ErrorResponse(c, http.StatusConflict, "This prompt is already in the collection")
+	case errors.Is(err, services.ErrAlreadyEditor):
+		utils.ErrorResponse(c, http.StatusConflict, "This user is already an editor or invited")
+	case errors.Is(err, services.ErrInviteSelf):
+		utils.ErrorResponse(c, http.StatusBadRequest, "You already own this collection")
+	case errors.Is(err, services.ErrInvalidMediaType):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Unknown media kind")
+	case errors.Is(err, utils.ErrInvalidPageRequest):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to "+action+" collection")
+	}
+}
+
+// CreateCollection creates a collection owned by the caller
+func CreateCollection(c *gin.Context) {
+	var req models.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection, err := services.CreateCollection(currentActor(c), req)
+	if err != nil {
+		respondCollectionError(c, err, "create")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Collection created successfully", collection)
+}
+
+// GetCollection returns a collection with its owner and editors
+func GetCollection(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	collection, err := services.GetCollection(publicViewer(c), id)
+	if err != nil {
+		respondCollectionError(c, err, "fetch")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection retrieved successfully", collection)
+}
+
+// UpdateCollection changes a collection's name, description and visibility (Owner only)
+func UpdateCollection(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "update")
+		return
+	}
+
+	var req models.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection, err := services.UpdateCollection(currentActor(c), id, req)
+	if err != nil {
+		respondCollectionError(c, err, "update")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection updated successfully", collection)
+}
+
+// DeleteCollection deletes a collection (Owner only)
+func DeleteCollection(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "delete")
+		return
+	}
+
+	if err := services.DeleteCollection(currentActor(c), id); err != nil {
+		respondCollectionError(c, err, "delete")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection deleted successfully", nil)
+}
+
+// GetCollectionItems returns a page of a collection's prompts in order
+func GetCollectionItems(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.CollectionItemSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, pagination, err := services.ListCollectionItems(publicViewer(c), id, page)
+	if err != nil {
+		respondCollectionError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Collection items retrieved successfully", items, pagination)
+}
+
+// AddCollectionItem adds a prompt to a collection (Owner or editor)
+func AddCollectionItem(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "edit")
+		return
+	}
+
+	var req models.AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := services.AddCollectionItem(currentActor(c), id, req)
+	if err != nil {
+		respondCollectionError(c, err, "edit")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Prompt added to collection", item)
+}
+
+// MoveCollectionItem moves an item to a new position (Owner or editor)
+func MoveCollectionItem(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	itemID, itemOK := parseID(c, "itemId")
+	if !ok || !itemOK {
+		respondCollectionError(c, services.ErrNotFound, "edit")
+		return
+	}
+
+	var req models.MoveCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := services.MoveCollectionItem(currentActor(c), id, itemID, req.Position)
+	if err != nil {
+		respondCollectionError(c, err, "edit")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection item moved", item)
+}
+
+// RemoveCollectionItem removes an item from a collection (Owner or editor)
+func RemoveCollectionItem(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	itemID, itemOK := parseID(c, "itemId")
+	if !ok || !itemOK {
+		respondCollectionError(c, services.ErrNotFound, "edit")
+		return
+	}
+
+	if err := services.RemoveCollectionItem(currentActor(c), id, itemID); err != nil {
+		respondCollectionError(c, err, "edit")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prompt removed from collection", nil)
+}
+
+// InviteCollectionEditor invites a user to curate a collection (Owner only)
+func InviteCollectionEditor(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "share")
+		return
+	}
+
+	var req models.InviteEditorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	editor, err := services.InviteEditor(currentActor(c), id, req.Username)
+	if err != nil {
+		respondCollectionError(c, err, "share")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Editor invited successfully", editor)
+}
+
+// AcceptCollectionInvite accepts the caller's invitation to edit a collection
+func AcceptCollectionInvite(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCollectionError(c, services.ErrNotFound, "join")
+		return
+	}
+
+	collection, err := services.AcceptInvite(currentActor(c), id)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "No pending invitation for this collection")
+			return
+		}
+		respondCollectionError(c, err, "join")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Invitation accepted", collection)
+}
+
+// RemoveCollectionEditor removes an editor or invitation (Owner, or the editor themselves)
+func RemoveCollectionEditor(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	userID, userOK := parseID(c, "userId")
+	if !ok || !userOK {
+		respondCollectionError(c, services.ErrNotFound, "edit")
+		return
+	}
+
+	if err := services.RemoveEditor(currentActor(c), id, userID); err != nil {
+		respondCollectionError(c, err, "edit")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Editor removed successfully", nil)
+}
+
+// GetMyCollectionInvites returns the collections the caller has been invited to edit
+func GetMyCollectionInvites(c *gin.Context) {
+	collections, err := services.ListInvites(currentActor(c))
+	if err != nil {
+		respondCollectionError(c, err, "fetch")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection invitations retrieved successfully", collections)
+}
+
+// GetMyCollections returns a page of the collections the caller owns or edits
+func GetMyCollections(c *gin.Context) {
+	userID, _ := currentUser(c)
+	listCollections(c, userID, true)
+}
+
+// GetUserCollections returns a page of a user's collections visible to the caller
+func GetUserCollections(c *gin.Context) {
+	userID, ok := parseID(c, "id")
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+	listCollections(c, userID, false)
+}
+
+func listCollections(c *gin.Context, userID uint, shared bool) {
+	page, err := utils.ParsePageRequest(c, services.CollectionSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collections, pagination, err := services.ListUserCollections(publicViewer(c), userID, shared, page)
+	if err != nil {
+		respondCollectionError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Collections retrieved successfully", collections, pagination)
+}
+
+// GetCollections returns a page of the collections a prompt appears in
+func (pc *PromptController[T, PT]) GetCollections(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.CollectionSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collections, pagination, err := services.ListPromptCollections(publicViewer(c), pc.service.Kind.Name, id, page)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			pc.respondError(c, err, "fetch")
+			return
+		}
+		respondCollectionError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Collections retrieved successfully", collections, pagination)
+}
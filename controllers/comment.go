@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondCommentError maps a comment service error to an HTTP error response
+func respondCommentError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Comment not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, "You don't have permission to "+action+" this comment")
+	case errors.Is(err, services.ErrMissingFields):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Comment text is required")
+	case errors.Is(err, services.ErrInvalidParent):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Parent comment is not on this prompt")
+	case errors.Is(err, services.ErrCommentDeleted):
+		utils.ErrorResponse(c, http.StatusConflict, "This comment has been deleted")
+	case errors.Is(err, utils.ErrInvalidPageRequest):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to "+action+" comment")
+	}
+}
+
+// GetComments returns a page of a prompt's top-level comments, newest first
+func (pc *PromptController[T, PT]) GetComments(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.CommentSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comments, pagination, err := services.ListComments(publicViewer(c), pc.service.Kind.Name, id, page)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			pc.respondError(c, err, "fetch")
+			return
+		}
+		respondCommentError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Comments retrieved successfully", comments, pagination)
+}
+
+// CreateComment adds a comment or reply to a prompt
+func (pc *PromptController[T, PT]) CreateComment(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "comment on")
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := services.CreateComment(currentActor(c), pc.service.Kind.Name, id, req.Body, req.ParentID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			pc.respondError(c, err, "comment on")
+			return
+		}
+		respondCommentError(c, err, "create")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Comment posted successfully", comment)
+}
+
+// GetCommentReplies returns a page of the direct replies to a comment
+func GetCommentReplies(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCommentError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.CommentSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if c.Query("order") == "" {
+		page.Asc = true // Replies read oldest first
+	}
+
+	replies, pagination, err := services.ListReplies(publicViewer(c), id, page)
+	if err != nil {
+		respondCommentError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Replies retrieved successfully", replies, pagination)
+}
+
+// UpdateComment edits the caller's own comment
+func UpdateComment(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCommentError(c, services.ErrNotFound, "edit")
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := services.UpdateComment(currentActor(c), id, req.Body)
+	if err != nil {
+		respondCommentError(c, err, "edit")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment updated successfully", comment)
+}
+
+// DeleteComment soft deletes a comment (Author, prompt owner or moderator)
+func DeleteComment(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondCommentError(c, services.ErrNotFound, "delete")
+		return
+	}
+
+	if err := services.DeleteComment(currentActor(c), id); err != nil {
+		respondCommentError(c, err, "delete")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Comment deleted successfully", nil)
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondFollowError maps a follow service error to an HTTP error response
+func respondFollowError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+	case errors.Is(err, services.ErrFollowSelf):
+		utils.ErrorResponse(c, http.StatusBadRequest, "You cannot follow yourself")
+	case errors.Is(err, utils.ErrInvalidPageRequest):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to "+action)
+	}
+}
+
+// GetPublicProfile returns a user's public profile with follower counts
+func GetPublicProfile(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondFollowError(c, services.ErrNotFound, "fetch profile")
+		return
+	}
+
+	profile, err := services.GetPublicProfile(publicViewer(c), id)
+	if err != nil {
+		respondFollowError(c, err, "fetch profile")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", profile)
+}
+
+// FollowUser makes the caller follow a user
+func FollowUser(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondFollowError(c, services.ErrNotFound, "follow user")
+		return
+	}
+
+	status, err := services.Follow(currentActor(c), id)
+	if err != nil {
+		respondFollowError(c, err, "follow user")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User followed", status)
+}
+
+// UnfollowUser stops the caller following a user
+func UnfollowUser(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondFollowError(c, services.ErrNotFound, "unfollow user")
+		return
+	}
+
+	status, err := services.Unfollow(currentActor(c), id)
+	if err != nil {
+		respondFollowError(c, err, "unfollow user")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unfollowed", status)
+}
+
+// GetFollowers returns a page of the users following a user, most recent first
+func GetFollowers(c *gin.Context) {
+	listFollows(c, services.ListFollowers, "Followers")
+}
+
+// GetFollowing returns a page of the users a user follows, most recent first
+func GetFollowing(c *gin.Context) {
+	listFollows(c, services.ListFollowing, "Following")
+}
+
+func listFollows(c *gin.Context, list func(uint, utils.PageRequest) ([]models.FollowEntry, *utils.Pagination, error), label string) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondFollowError(c, services.ErrNotFound, "fetch "+label)
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.FollowSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, pagination, err := list(id, page)
+	if err != nil {
+		respondFollowError(c, err, "fetch "+label)
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, label+" retrieved successfully", entries, pagination)
+}
+
+// parseFeedPage reads limit and cursor for a feed, which doesn't support page numbers
+func parseFeedPage(c *gin.Context) (utils.PageRequest, bool) {
+	page, err := utils.ParsePageRequest(c, services.FeedSortKeys)
+	if err == nil && page.Page > 0 {
+		err = errors.New("feeds use cursor, not page, pagination")
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return page, false
+	}
+	return page, true
+}
+
+// GetFollowingFeed returns a page of prompts newly published by creators the caller
+// follows, across every media kind
+func GetFollowingFeed(c *gin.Context) {
+	page, ok := parseFeedPage(c)
+	if !ok {
+		return
+	}
+
+	items, pagination, err := services.FollowingFeed(publicViewer(c), page.Limit, page.Cursor)
+	if err != nil {
+		respondFollowError(c, err, "fetch feed")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Feed retrieved successfully", items, pagination)
+}
+
+// GetForYouFeed returns a page of published prompts ranked for the caller by their
+// interests, like history and what's trending
+func GetForYouFeed(c *gin.Context) {
+	page, err := utils.ParsePageRequest(c, services.RecommendSortKeys)
+	if err == nil && page.Cursor != "" {
+		err = errors.New("the for-you feed uses page, not cursor, pagination")
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	items, pagination, err := services.Recommend(publicViewer(c), page)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch feed")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Feed retrieved successfully", items, pagination)
+}
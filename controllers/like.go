@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Like adds the caller's like to a prompt
+func (pc *PromptController[T, PT]) Like(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "like")
+		return
+	}
+
+	status, err := pc.service.Like(publicViewer(c), id)
+	if err != nil {
+		pc.respondError(c, err, "like")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pc.service.Kind.Label+" prompt liked", status)
+}
+
+// Unlike removes the caller's like from a prompt
+func (pc *PromptController[T, PT]) Unlike(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "unlike")
+		return
+	}
+
+	status, err := pc.service.Unlike(publicViewer(c), id)
+	if err != nil {
+		pc.respondError(c, err, "unlike")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pc.service.Kind.Label+" prompt unliked", status)
+}
+
+// GetMyLikes returns a page of the prompts the caller has liked, across every media kind
+func GetMyLikes(c *gin.Context) {
+	page, err := utils.ParsePageRequest(c, services.LikeSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	likes, pagination, err := services.ListLikes(publicViewer(c), page)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch liked prompts")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Liked prompts retrieved successfully", likes, pagination)
+}
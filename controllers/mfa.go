@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondMFAError maps a two-factor service error to an HTTP error response
+func respondMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTOTPCode):
+		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrTOTPAlreadyEnabled),
+		errors.Is(err, services.ErrTOTPNotEnabled),
+		errors.Is(err, services.ErrTOTPNotSetUp):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update two-factor authentication")
+	}
+}
+
+// loadCurrentUser loads the authenticated user
+func loadCurrentUser(c *gin.Context) (*models.User, bool) {
+	userID, _ := currentUser(c)
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return nil, false
+	}
+	return &user, true
+}
+
+// SetupTOTP generates a TOTP secret for the current user to add to an authenticator app
+func SetupTOTP(c *gin.Context) {
+	user, ok := loadCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	setup, err := services.BeginTOTPSetup(user)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the code with your authenticator app, then confirm it", setup)
+}
+
+// EnableTOTP confirms the TOTP secret and returns recovery codes
+func EnableTOTP(c *gin.Context) {
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, ok := loadCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	codes, err := services.EnableTOTP(user, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled. Store your recovery codes safely", models.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTOTP turns two-factor authentication off
+func DisableTOTP(c *gin.Context) {
+	var req models.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, ok := loadCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if !utils.CheckPassword(user.PasswordHash, req.Password) {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid password")
+		return
+	}
+
+	if err := services.DisableTOTP(user, req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, ok := loadCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	codes, err := services.RegenerateRecoveryCodes(user, req.Code)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recovery codes regenerated", models.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// VerifyLoginMFA completes a login that returned an MFA challenge
+func VerifyLoginMFA(c *gin.Context) {
+	var req models.VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Either code or recovery_code is required")
+		return
+	}
+
+	claims, err := utils.ValidateMFAToken(req.MFAToken)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired MFA token")
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, claims.UserID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired MFA token")
+		return
+	}
+
+	if !user.IsActive {
+		utils.ErrorResponse(c, http.StatusForbidden, "Account is deactivated")
+		return
+	}
+
+	// Second-factor guesses share the login lockout
+	if loginLockedOut(c, user.Email) {
+		return
+	}
+
+	if err := services.VerifySecondFactor(&user, req.Code, req.RecoveryCode); err != nil {
+		if errors.Is(err, services.ErrInvalidTOTPCode) {
+			recordLoginFailure(c, user.Email)
+		}
+		respondMFAError(c, err)
+		return
+	}
+
+	// Update last login
+	now := time.Now()
+	config.DB.Model(&user).Update("last_login", now)
+	user.LastLogin = &now
+
+	tokens, err := services.StartSession(&user, sessionClient(c), true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	// Remove password hash from response
+	user.PasswordHash = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", models.AuthResponse{
+		AuthTokens: *tokens,
+		User:       user,
+	})
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkModerate applies one moderation action to prompts of any media kind and
+// reports the outcome per item (Moderators only; feature and delete need prompts.manage)
+func BulkModerate(c *gin.Context) {
+	var req models.BulkModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	op := services.BulkOperation{
+		Action:   req.Action,
+		Decision: services.Decision{Reason: req.Reason, Details: req.Details, Note: req.Note},
+	}
+
+	report, err := services.BulkModerate(currentActor(c), op, req.Items)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReason) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Rejection reason must be one of: "+strings.Join(config.AppConfig.RejectionReasons, "; "))
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to run bulk action")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bulk action completed", report)
+}
+
+// queueSortKeys is the only ordering of the moderation queue: oldest first
+var queueSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true, Asc: true},
+}
+
+// respondQueueError maps a moderation queue error to an HTTP error response
+func respondQueueError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "Prompt or claim not found")
+	case errors.Is(err, services.ErrInvalidMediaType):
+		utils.ErrorResponse(c, http.StatusBadRequest, "kind must be image, gif or video")
+	case errors.Is(err, services.ErrInvalidTransition):
+		utils.ErrorResponse(c, http.StatusConflict, "Only pending prompts can be claimed")
+	case errors.Is(err, services.ErrClaimed):
+		utils.ErrorResponse(c, http.StatusConflict, "This prompt is claimed by another moderator")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to "+action)
+	}
+}
+
+// queueKind reads and validates the optional ?kind= filter
+func queueKind(c *gin.Context) (string, bool) {
+	kind := c.Query("kind")
+	if kind == "" {
+		return "", true
+	}
+	if _, ok := services.LookupKind(kind); !ok {
+		respondQueueError(c, services.ErrInvalidMediaType, "")
+		return "", false
+	}
+	return kind, true
+}
+
+// GetModerationQueue returns pending prompts of every media kind, oldest first (Moderators only)
+func GetModerationQueue(c *gin.Context) {
+	page, err := utils.ParsePageRequest(c, queueSortKeys)
+	if err == nil && page.Cursor != "" {
+		err = errors.New("the moderation queue uses page, not cursor, pagination")
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if page.Page == 0 {
+		page.Page = 1
+	}
+
+	kind, ok := queueKind(c)
+	if !ok {
+		return
+	}
+
+	filter := services.QueueFilter{
+		Kind:      kind,
+		Unclaimed: c.Query("unclaimed") == "true",
+	}
+	if c.Query("mine") == "true" {
+		filter.ClaimedBy, _ = currentUser(c)
+	}
+
+	items, total, err := services.ListQueue(filter, page.Limit, page.Page)
+	if err != nil {
+		respondQueueError(c, err, "fetch moderation queue")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Moderation queue retrieved successfully", items, &utils.Pagination{
+		Limit:      page.Limit,
+		Total:      total,
+		Page:       page.Page,
+		TotalPages: int((total + int64(page.Limit) - 1) / int64(page.Limit)),
+		Sort:       "created_at",
+		Order:      "asc",
+	})
+}
+
+// ClaimQueueItem locks a pending prompt to the caller while they review it (Moderators only)
+func ClaimQueueItem(c *gin.Context) {
+	var req models.ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	claim, err := services.Claim(currentActor(c), req.Kind, req.ID)
+	if err != nil {
+		respondQueueError(c, err, "claim prompt")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prompt claimed successfully", claim)
+}
+
+// ClaimNextQueueItems claims the oldest unclaimed pending prompts (Moderators only)
+func ClaimNextQueueItems(c *gin.Context) {
+	kind, ok := queueKind(c)
+	if !ok {
+		return
+	}
+
+	count := 1
+	if value := c.Query("count"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 20 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "count must be between 1 and 20")
+			return
+		}
+		count = n
+	}
+
+	claims, err := services.ClaimNext(currentActor(c), kind, count)
+	if err != nil {
+		respondQueueError(c, err, "claim prompts")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Prompts claimed successfully", claims)
+}
+
+// ReleaseQueueClaim gives up a claim so another moderator can review the prompt (Moderators only)
+func ReleaseQueueClaim(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		respondQueueError(c, services.ErrNotFound, "")
+		return
+	}
+
+	if err := services.ReleaseClaim(currentActor(c), c.Param("kind"), id); err != nil {
+		respondQueueError(c, err, "release claim")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Claim released successfully", nil)
+}
+
+// GetModerationQueueStats reports queue size, oldest item age and per-moderator
+// throughput over ?window= (default 24h) (Moderators only)
+func GetModerationQueueStats(c *gin.Context) {
+	window := 24 * time.Hour
+	if value := c.Query("window"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "window must be a positive duration such as 24h or 168h")
+			return
+		}
+		window = d
+	}
+
+	stats, err := services.GetQueueStats(window)
+	if err != nil {
+		respondQueueError(c, err, "fetch queue statistics")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Queue statistics retrieved successfully", stats)
+}
@@ -1,14 +1,17 @@
 package controllers
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"time"
 
 	"ai-of-the-world-backend/config"
 	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
 	"ai-of-the-world-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // SendOTP sends an OTP to the user's email
@@ -37,6 +40,16 @@ func SendOTP(c *gin.Context) {
 		}
 	}
 
+	// Enforce the resend cooldown
+	var lastOTP models.OTP
+	if err := config.DB.Where("email = ? AND purpose = ?", req.Email, req.Purpose).
+		Order("created_at DESC").First(&lastOTP).Error; err == nil {
+		if wait := time.Until(lastOTP.CreatedAt.Add(config.AppConfig.OTPResendCooldown)); wait > 0 {
+			utils.TooManyRequestsResponse(c, wait, "Please wait before requesting another OTP")
+			return
+		}
+	}
+
 	// Generate OTP
 	otp, err := utils.GenerateOTP()
 	if err != nil {
@@ -81,10 +94,13 @@ func VerifyOTP(c *gin.Context) {
 		return
 	}
 
-	// Find OTP
+	// Find the latest outstanding OTP for this email, and purpose when given
 	var otpRecord models.OTP
-	if err := config.DB.Where("email = ? AND otp = ? AND verified = ?", req.Email, req.OTP, false).
-		Order("created_at DESC").First(&otpRecord).Error; err != nil {
+	lookup := config.DB.Where("email = ? AND verified = ?", req.Email, false)
+	if req.Purpose != "" {
+		lookup = lookup.Where("purpose = ?", req.Purpose)
+	}
+	if err := lookup.Order("created_at DESC").First(&otpRecord).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid OTP")
 		return
 	}
@@ -95,9 +111,30 @@ func VerifyOTP(c *gin.Context) {
 		return
 	}
 
+	// Claim an attempt before comparing so concurrent guesses can't exceed the limit
+	maxAttempts := config.AppConfig.OTPMaxAttempts
+	claim := config.DB.Model(&otpRecord).Where("attempts < ?", maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if claim.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to verify OTP")
+		return
+	}
+	exhausted := claim.RowsAffected == 0
+
+	if exhausted || subtle.ConstantTimeCompare([]byte(otpRecord.OTP), []byte(req.OTP)) != 1 {
+		// The code is burned once the limit is reached. The exhausted row is kept
+		// rather than deleted so the resend cooldown still applies.
+		if exhausted || otpRecord.Attempts+1 >= maxAttempts {
+			retryAfter := time.Until(otpRecord.CreatedAt.Add(config.AppConfig.OTPResendCooldown))
+			utils.TooManyRequestsResponse(c, retryAfter, "Too many failed attempts. Please request a new OTP")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid OTP")
+		return
+	}
+
 	// Mark OTP as verified
-	otpRecord.Verified = true
-	config.DB.Save(&otpRecord)
+	config.DB.Model(&otpRecord).Update("verified", true)
 
 	utils.SuccessResponse(c, http.StatusOK, "OTP verified successfully", nil)
 }
@@ -151,7 +188,7 @@ func SignupWithOTP(c *gin.Context) {
 		Email:         req.Email,
 		PasswordHash:  hashedPassword,
 		FullName:      req.FullName,
-		Role:          "user",
+		Role:          models.RoleUser,
 		IsActive:      true,
 		EmailVerified: true, // Email is verified via OTP
 	}
@@ -164,8 +201,8 @@ func SignupWithOTP(c *gin.Context) {
 	// Delete used OTP
 	config.DB.Delete(&otpRecord)
 
-	// Generate token
-	token, err := utils.GenerateToken(user.ID, user.Username, user.Email, user.Role)
+	// Start a session and issue tokens
+	tokens, err := services.StartSession(&user, sessionClient(c), false)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to generate token")
 		return
@@ -175,8 +212,8 @@ func SignupWithOTP(c *gin.Context) {
 	user.PasswordHash = ""
 
 	utils.SuccessResponse(c, http.StatusCreated, "User registered successfully", models.AuthResponse{
-		Token: token,
-		User:  user,
+		AuthTokens: *tokens,
+		User:       user,
 	})
 }
 
@@ -219,7 +256,7 @@ func ResetPassword(c *gin.Context) {
 
 	// Update password
 	user.PasswordHash = hashedPassword
-	if err := config.DB.Save(&user).Error; err != nil {
+	if err := config.DB.Model(&user).Update("password_hash", hashedPassword).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update password")
 		return
 	}
@@ -227,5 +264,11 @@ func ResetPassword(c *gin.Context) {
 	// Delete used OTP
 	config.DB.Delete(&otpRecord)
 
+	// Sign the user out everywhere
+	if err := services.RevokeAllSessions(user.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Password updated but failed to revoke sessions")
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
 }
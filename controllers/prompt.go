@@ -0,0 +1,417 @@
+package controllers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptController exposes the prompt endpoints of one media kind
+type PromptController[T any, PT services.PromptModel[T]] struct {
+	service *services.PromptService[T, PT]
+}
+
+// NewPromptController creates the handlers for a prompt service
+func NewPromptController[T any, PT services.PromptModel[T]](service *services.PromptService[T, PT]) *PromptController[T, PT] {
+	return &PromptController[T, PT]{service: service}
+}
+
+// Prompt handlers for each media kind
+var (
+	Images = NewPromptController(services.Images)
+	GIFs   = NewPromptController(services.GIFs)
+	Videos = NewPromptController(services.Videos)
+)
+
+// currentUser returns the authenticated user's ID and role
+func currentUser(c *gin.Context) (uint, string) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	id, _ := userID.(uint)
+	roleName, _ := role.(string)
+	return id, roleName
+}
+
+// currentActor describes the authenticated user for services that audit their actions
+func currentActor(c *gin.Context) services.Actor {
+	userID, role := currentUser(c)
+	return services.Actor{
+		UserID:    userID,
+		Username:  c.GetString("username"),
+		Role:      role,
+		IPAddress: c.ClientIP(),
+		RequestID: c.GetString("requestID"),
+	}
+}
+
+// parseID parses a numeric path parameter
+func parseID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError maps a service error to an HTTP error response
+func (pc *PromptController[T, PT]) respondError(c *gin.Context, err error, action string) {
+	kind := pc.service.Kind
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, kind.Label+" prompt not found")
+	case errors.Is(err, services.ErrForbidden):
+		utils.ErrorResponse(c, http.StatusForbidden, "You don't have permission to "+action+" this prompt")
+	case errors.Is(err, services.ErrMissingFields):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Missing required fields")
+	case errors.Is(err, services.ErrInvalidMediaType):
+		utils.ErrorResponse(c, http.StatusBadRequest, kind.InvalidTypeMessage)
+	case errors.Is(err, services.ErrUploadFailed):
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to upload "+kind.Noun+": "+err.Error())
+	case errors.Is(err, services.ErrNotApproved):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Only approved prompts can be published")
+	case errors.Is(err, services.ErrInvalidReason):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Rejection reason must be one of: "+strings.Join(config.AppConfig.RejectionReasons, "; "))
+	case errors.Is(err, services.ErrClaimed):
+		utils.ErrorResponse(c, http.StatusConflict, "This "+kind.Noun+" prompt is claimed by another moderator")
+	case errors.Is(err, services.ErrInvalidTransition):
+		utils.ErrorResponse(c, http.StatusConflict, "Cannot "+action+" a "+kind.Noun+" prompt in its current status")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to "+action+" "+kind.Noun+" prompt")
+	}
+}
+
+// Upload handles a new media submission
+func (pc *PromptController[T, PT]) Upload(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	kind := pc.service.Kind
+
+	// Parse multipart form
+	if err := c.Request.ParseMultipartForm(config.AppConfig.MaxUploadSize); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File too large or invalid")
+		return
+	}
+
+	file, header, err := c.Request.FormFile(kind.FormField)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, kind.MissingFileMessage)
+		return
+	}
+	defer file.Close()
+
+	prompt, err := pc.service.Create(userID.(uint), promptInput(c), file, header)
+	if err != nil {
+		pc.respondError(c, err, "save")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, kind.Label+" uploaded successfully", prompt)
+}
+
+// promptInput reads the prompt fields of a submission form; TagIDs stays nil
+// when the form has no tags field
+func promptInput(c *gin.Context) services.PromptInput {
+	input := services.PromptInput{
+		ProjectTitle:   c.PostForm("project_title"),
+		Prompt:         c.PostForm("prompt"),
+		TechnicalNotes: c.PostForm("technical_notes"),
+		ModelOrTool:    c.PostForm("model_or_tool"),
+		CreatorCredit:  c.PostForm("creator_credit"),
+	}
+	if tags, ok := c.GetPostForm("tags"); ok { // Comma-separated tag IDs
+		input.TagIDs = strings.Split(tags, ",")
+	}
+	return input
+}
+
+// Resubmit sends the caller's rejected prompt back for review, applying any edited
+// fields and an optional replacement file (Owner only)
+func (pc *PromptController[T, PT]) Resubmit(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "resubmit")
+		return
+	}
+
+	kind := pc.service.Kind
+
+	// The form may carry only edited fields; the file is optional
+	if err := c.Request.ParseMultipartForm(config.AppConfig.MaxUploadSize); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File too large or invalid")
+		return
+	}
+
+	var (
+		file   multipart.File
+		header *multipart.FileHeader
+	)
+	if c.Request.MultipartForm != nil {
+		if f, h, err := c.Request.FormFile(kind.FormField); err == nil {
+			defer f.Close()
+			file, header = f, h
+		}
+	}
+
+	prompt, err := pc.service.Resubmit(currentActor(c), id, promptInput(c), file, header)
+	if err != nil {
+		pc.respondError(c, err, "resubmit")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, kind.Label+" prompt resubmitted for review", prompt)
+}
+
+// publicViewer returns the viewer for a public route; optional authentication
+// lets owners see their own unpublished submissions
+func publicViewer(c *gin.Context) services.Viewer {
+	userID, _ := currentUser(c)
+	return services.Viewer{UserID: userID, ClientIP: c.ClientIP()}
+}
+
+// adminViewer returns the unrestricted viewer used by admin routes
+func adminViewer(c *gin.Context) services.Viewer {
+	userID, _ := currentUser(c)
+	return services.Viewer{UserID: userID, Unrestricted: true}
+}
+
+// List returns a page of published prompts, plus the caller's own submissions
+func (pc *PromptController[T, PT]) List(c *gin.Context) {
+	pc.list(c, publicViewer(c))
+}
+
+// AdminList returns a page of prompts in any status (Admin only)
+func (pc *PromptController[T, PT]) AdminList(c *gin.Context) {
+	pc.list(c, adminViewer(c))
+}
+
+func (pc *PromptController[T, PT]) list(c *gin.Context, viewer services.Viewer) {
+	page, err := utils.ParsePageRequest(c, pc.service.SortKeys())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := services.PromptFilter{
+		Status:     c.Query("status"),
+		UserID:     c.Query("user_id"),
+		IsFeatured: c.Query("is_featured") == "true",
+	}
+
+	prompts, pagination, err := pc.service.List(viewer, filter, page)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		pc.respondError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, pc.service.Kind.Label+" prompts retrieved successfully", prompts, pagination)
+}
+
+// Get returns a single prompt visible to the caller and counts the view
+func (pc *PromptController[T, PT]) Get(c *gin.Context) {
+	pc.get(c, publicViewer(c), true)
+}
+
+// AdminGet returns a single prompt in any status (Admin only)
+func (pc *PromptController[T, PT]) AdminGet(c *gin.Context) {
+	pc.get(c, adminViewer(c), false)
+}
+
+func (pc *PromptController[T, PT]) get(c *gin.Context, viewer services.Viewer, countView bool) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	prompt, err := pc.service.Get(viewer, id)
+	if err != nil {
+		pc.respondError(c, err, "fetch")
+		return
+	}
+	if countView {
+		pc.service.RecordView(viewer, prompt)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pc.service.Kind.Label+" prompt retrieved successfully", prompt)
+}
+
+// Download counts a download of a visible prompt and redirects to its asset
+func (pc *PromptController[T, PT]) Download(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "download")
+		return
+	}
+
+	url, err := pc.service.Download(publicViewer(c), id)
+	if err != nil {
+		pc.respondError(c, err, "download")
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// Delete deletes a prompt (Admin or Owner)
+func (pc *PromptController[T, PT]) Delete(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "delete")
+		return
+	}
+
+	if err := pc.service.Delete(currentActor(c), id); err != nil {
+		pc.respondError(c, err, "delete")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pc.service.Kind.Label+" prompt deleted successfully", nil)
+}
+
+// Update updates a prompt's details (Admin or Owner)
+func (pc *PromptController[T, PT]) Update(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "update")
+		return
+	}
+
+	var req models.UpdatePromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompt, err := pc.service.Update(currentActor(c), id, req)
+	if err != nil {
+		pc.respondError(c, err, "update")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pc.service.Kind.Label+" prompt updated successfully", prompt)
+}
+
+// moderate runs a moderation action against the prompt in the path
+func (pc *PromptController[T, PT]) moderate(c *gin.Context, action, done string, fn func(actor services.Actor, id uint) (PT, error)) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, action)
+		return
+	}
+
+	prompt, err := fn(currentActor(c), id)
+	if err != nil {
+		pc.respondError(c, err, action)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, pc.service.Kind.Label+" prompt "+done+" successfully", prompt)
+}
+
+// Approve approves a prompt (Admin only)
+func (pc *PromptController[T, PT]) Approve(c *gin.Context) {
+	pc.moderate(c, "approve", "approved", pc.service.Approve)
+}
+
+// Reject rejects a prompt with a reason shown to its owner (Admin only)
+func (pc *PromptController[T, PT]) Reject(c *gin.Context) {
+	var req models.RejectPromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	decision := services.Decision{Reason: req.Reason, Details: req.Details, Note: req.Note}
+	pc.moderate(c, "reject", "rejected", func(actor services.Actor, id uint) (PT, error) {
+		return pc.service.Reject(actor, id, decision)
+	})
+}
+
+// Publish publishes an approved prompt (Admin only)
+func (pc *PromptController[T, PT]) Publish(c *gin.Context) {
+	pc.moderate(c, "publish", "published", pc.service.Publish)
+}
+
+// Unpublish unpublishes a prompt (Admin only)
+func (pc *PromptController[T, PT]) Unpublish(c *gin.Context) {
+	pc.moderate(c, "unpublish", "unpublished", pc.service.Unpublish)
+}
+
+// GetNotes lists a prompt's internal moderator notes (Moderators only)
+func (pc *PromptController[T, PT]) GetNotes(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	notes, err := pc.service.Notes(id)
+	if err != nil {
+		pc.respondError(c, err, "fetch")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Moderator notes retrieved successfully", notes)
+}
+
+// AddNote attaches an internal moderator note to a prompt (Moderators only)
+func (pc *PromptController[T, PT]) AddNote(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "annotate")
+		return
+	}
+
+	var req models.CreateModerationNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	note, err := pc.service.AddNote(currentActor(c), id, req.Note)
+	if err != nil {
+		pc.respondError(c, err, "annotate")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Moderator note added successfully", note)
+}
+
+// GetRejectionReasons lists the canned reasons moderators pick from when rejecting
+func GetRejectionReasons(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Rejection reasons retrieved successfully", config.AppConfig.RejectionReasons)
+}
+
+// GetHistory lists a prompt's review history: submissions, decisions and resubmissions (Moderators only)
+func (pc *PromptController[T, PT]) GetHistory(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	events, err := pc.service.History(id)
+	if err != nil {
+		pc.respondError(c, err, "fetch")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Moderation history retrieved successfully", events)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Report flags a prompt for moderator review
+func (pc *PromptController[T, PT]) Report(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "report")
+		return
+	}
+
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := services.CreateReport(currentActor(c), pc.service.Kind.Name, id, req.Reason, req.Details)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAlreadyReported):
+			utils.ErrorResponse(c, http.StatusConflict, "You have already reported this prompt")
+		case errors.Is(err, services.ErrReportOwnPrompt):
+			utils.ErrorResponse(c, http.StatusBadRequest, "You cannot report your own prompt")
+		default:
+			pc.respondError(c, err, "report")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Report submitted. Thank you for helping keep the community safe", report)
+}
+
+// GetReports returns a page of reports for triage, filtered by status, kind,
+// target_id and reason (Moderators only)
+func GetReports(c *gin.Context) {
+	page, err := utils.ParsePageRequest(c, services.ReportSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := services.ReportFilter{
+		Status:   c.DefaultQuery("status", models.ReportStatusOpen),
+		Kind:     c.Query("kind"),
+		TargetID: c.Query("target_id"),
+		Reason:   c.Query("reason"),
+	}
+	if filter.Status == "all" {
+		filter.Status = ""
+	}
+
+	reports, pagination, err := services.ListReports(filter, page)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch reports")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Reports retrieved successfully", reports, pagination)
+}
+
+// ResolveReport triages a report by approving, rejecting, publishing or unpublishing
+// its prompt, or dismissing it; every open report on the prompt is closed (Moderators only)
+func ResolveReport(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "Report not found")
+		return
+	}
+
+	var req models.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	decision := services.Decision{Reason: req.Reason, Details: req.Details, Note: req.Note}
+	resolution, err := services.ResolveReport(currentActor(c), id, req.Action, decision)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "Report or prompt not found")
+		case errors.Is(err, services.ErrReportResolved):
+			utils.ErrorResponse(c, http.StatusConflict, "Report is already resolved")
+		case errors.Is(err, services.ErrInvalidReason):
+			utils.ErrorResponse(c, http.StatusBadRequest, "Rejection reason must be one of: "+strings.Join(config.AppConfig.RejectionReasons, "; "))
+		case errors.Is(err, services.ErrInvalidTransition), errors.Is(err, services.ErrNotApproved):
+			utils.ErrorResponse(c, http.StatusConflict, "Cannot "+req.Action+" the prompt in its current status")
+		case errors.Is(err, services.ErrClaimed):
+			utils.ErrorResponse(c, http.StatusConflict, "This prompt is claimed by another moderator")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to resolve report")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report resolved successfully", resolution)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDList parses an optional comma-separated list of IDs query parameter,
+// dropping duplicates
+func parseIDList(c *gin.Context, name string) ([]uint, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	var ids []uint
+	seen := map[uint]bool{}
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			return nil, errors.New("invalid " + name + ": use comma-separated IDs")
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// Search returns a page of published prompts of every kind matching a text query and
+// filters, with tag and model facet counts
+func Search(c *gin.Context) {
+	page, err := utils.ParsePageRequest(c, services.SearchSortKeys)
+	if err == nil && page.Cursor != "" {
+		err = errors.New("search uses page, not cursor, pagination")
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := services.SearchFilter{
+		Query:       c.Query("q"),
+		Kind:        c.Query("kind"),
+		ModelOrTool: c.Query("model"),
+		IsFeatured:  c.Query("is_featured") == "true",
+	}
+	if filter.TagIDs, err = parseIDList(c, "tags"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, pagination, err := services.Search(publicViewer(c), filter, page)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMediaType):
+			utils.ErrorResponse(c, http.StatusBadRequest, "kind must be image, gif or video")
+		case errors.Is(err, services.ErrEmptySearch):
+			utils.ErrorResponse(c, http.StatusBadRequest, "Search query has no searchable words")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search prompts")
+		}
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Search results", results, pagination)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionClient describes the client making the request
+func sessionClient(c *gin.Context) models.SessionClient {
+	return models.SessionClient{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// currentSessionID returns the session of the access token in use
+func currentSessionID(c *gin.Context) uint {
+	sessionID, _ := c.Get("sessionID")
+	id, _ := sessionID.(uint)
+	return id
+}
+
+// GetMySessions lists the current user's active sessions
+func GetMySessions(c *gin.Context) {
+	userID, _ := currentUser(c)
+
+	sessions, err := services.ListActiveSessions(userID, currentSessionID(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeMySession signs the current user out of one session
+func RevokeMySession(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		utils.ErrorResponse(c, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	userID, _ := currentUser(c)
+
+	var session models.Session
+	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	if err := services.RevokeSession(userID, session.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeMyOtherSessions signs the current user out everywhere except this session
+func RevokeMyOtherSessions(c *gin.Context) {
+	userID, _ := currentUser(c)
+
+	if err := services.RevokeOtherSessions(userID, currentSessionID(c)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Other sessions revoked successfully", nil)
+}
+
+// GetUserSessions lists a user's active sessions (Admin only)
+func GetUserSessions(c *gin.Context) {
+	var user models.User
+	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	sessions, err := services.ListActiveSessions(user.ID, 0)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch sessions")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeUserSessions force-logs a user out of every session (Admin only)
+func RevokeUserSessions(c *gin.Context) {
+	var user models.User
+	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if err := services.RevokeAllSessions(user.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	if err := services.RecordAudit(config.DB, currentActor(c), services.AuditUserSignOut, services.AuditTargetUser, user.ID, nil, nil); err != nil {
+		log.Println("⚠️  Failed to record audit event:", err)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User logged out of all sessions", nil)
+}
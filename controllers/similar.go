@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSimilar returns a page of published prompts of any kind that resemble a prompt
+func (pc *PromptController[T, PT]) GetSimilar(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		pc.respondError(c, services.ErrNotFound, "fetch")
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.SimilarSortKeys)
+	if err == nil && page.Cursor != "" {
+		err = errors.New("similar prompts use page, not cursor, pagination")
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompts, pagination, err := services.Similar(publicViewer(c), pc.service.Kind.Name, id, page)
+	if err != nil {
+		pc.respondError(c, err, "fetch")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Similar prompts retrieved successfully", prompts, pagination)
+}
@@ -1,25 +1,39 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"ai-of-the-world-backend/config"
 	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
 	"ai-of-the-world-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// GetAllTags returns all tags
+// tagSortKeys are the orderings accepted by GetAllTags
+var tagSortKeys = []utils.SortKey{
+	{Name: "usage_count", Expr: "usage_count"},
+	{Name: "name", Expr: "name", Asc: true},
+	{Name: "created_at", Expr: "created_at", Time: true},
+}
+
+// GetAllTags returns a page of tags
 func GetAllTags(c *gin.Context) {
-	var tags []models.Tag
+	page, err := utils.ParsePageRequest(c, tagSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Optional filters
 	category := c.Query("category")
 	isActive := c.Query("is_active")
 
-	query := config.DB
+	query := config.DB.Model(&models.Tag{})
 
 	if category != "" {
 		query = query.Where("category = ?", category)
@@ -29,12 +43,18 @@ func GetAllTags(c *gin.Context) {
 		query = query.Where("is_active = ?", isActive == "true")
 	}
 
-	if err := query.Order("usage_count DESC, name ASC").Find(&tags).Error; err != nil {
+	tags := []models.Tag{}
+	pagination, err := utils.Paginate(query, page, &tags)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch tags")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Tags retrieved successfully", tags)
+	utils.PaginatedResponse(c, http.StatusOK, "Tags retrieved successfully", tags, pagination)
 }
 
 // GetTagByID returns a single tag by ID
@@ -72,7 +92,12 @@ func CreateTag(c *gin.Context) {
 		IsActive:    true,
 	}
 
-	if err := config.DB.Create(&tag).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&tag).Error; err != nil {
+			return err
+		}
+		return services.RecordAudit(tx, currentActor(c), services.AuditTagCreate, services.AuditTargetTag, tag.ID, nil, tag)
+	}); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create tag")
 		return
 	}
@@ -96,6 +121,8 @@ func UpdateTag(c *gin.Context) {
 		return
 	}
 
+	before := tag
+
 	// Check if new name conflicts with existing tag
 	if req.Name != "" && req.Name != tag.Name {
 		var existingTag models.Tag
@@ -118,7 +145,12 @@ func UpdateTag(c *gin.Context) {
 		tag.IsActive = *req.IsActive
 	}
 
-	if err := config.DB.Save(&tag).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&tag).Error; err != nil {
+			return err
+		}
+		return services.RecordAudit(tx, currentActor(c), services.AuditTagUpdate, services.AuditTargetTag, tag.ID, before, tag)
+	}); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update tag")
 		return
 	}
@@ -136,7 +168,15 @@ func DeleteTag(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&tag).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.RemoveTagFromInterests(tx, tag.ID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&tag).Error; err != nil {
+			return err
+		}
+		return services.RecordAudit(tx, currentActor(c), services.AuditTagDelete, services.AuditTargetTag, tag.ID, tag, nil)
+	}); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete tag")
 		return
 	}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scoreWindow reads ?window=, defaulting to daily
+func scoreWindow(c *gin.Context) (string, bool) {
+	window := c.DefaultQuery("window", models.WindowDaily)
+	if !models.IsScoreWindow(window) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "window must be one of "+strings.Join(models.ScoreWindows, ", "))
+		return "", false
+	}
+	return window, true
+}
+
+// GetTrending returns the top prompts of every media kind for a window
+func GetTrending(c *gin.Context) {
+	window, ok := scoreWindow(c)
+	if !ok {
+		return
+	}
+	kind := c.Query("kind")
+	if kind != "" {
+		if _, ok := services.LookupKind(kind); !ok {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Unknown media kind")
+			return
+		}
+	}
+
+	page, err := utils.ParsePageRequest(c, services.TrendingSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompts, pagination, err := services.ListTrending(publicViewer(c), window, kind, page)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch trending prompts")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Trending prompts retrieved successfully", prompts, pagination)
+}
+
+// GetLeaderboard returns the top creators for a window
+func GetLeaderboard(c *gin.Context) {
+	window, ok := scoreWindow(c)
+	if !ok {
+		return
+	}
+
+	page, err := utils.ParsePageRequest(c, services.TrendingSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, pagination, err := services.Leaderboard(window, page)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch leaderboard")
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Leaderboard retrieved successfully", entries, pagination)
+}
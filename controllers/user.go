@@ -1,24 +1,39 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"ai-of-the-world-backend/config"
 	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
 	"ai-of-the-world-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-// GetAllUsers returns all users (Admin only)
+// userSortKeys are the orderings accepted by GetAllUsers
+var userSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+	{Name: "username", Expr: "username", Asc: true},
+	{Name: "total_likes", Expr: "total_likes"},
+	{Name: "trending_score", Expr: "trending_score"},
+}
+
+// GetAllUsers returns a page of users (Admin only)
 func GetAllUsers(c *gin.Context) {
-	var users []models.User
+	page, err := utils.ParsePageRequest(c, userSortKeys)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Optional filters
 	role := c.Query("role")
 	isActive := c.Query("is_active")
 
-	query := config.DB
+	query := config.DB.Model(&models.User{})
 
 	if role != "" {
 		query = query.Where("role = ?", role)
@@ -28,10 +43,13 @@ func GetAllUsers(c *gin.Context) {
 		query = query.Where("is_active = ?", isActive == "true")
 	}
 
-	// Order by created_at desc
-	query = query.Order("created_at DESC")
-
-	if err := query.Find(&users).Error; err != nil {
+	users := []models.User{}
+	pagination, err := utils.Paginate(query, page, &users)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidPageRequest) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch users")
 		return
 	}
@@ -41,7 +59,7 @@ func GetAllUsers(c *gin.Context) {
 		users[i].PasswordHash = ""
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Users retrieved successfully", users)
+	utils.PaginatedResponse(c, http.StatusOK, "Users retrieved successfully", users, pagination)
 }
 
 // GetUserByID returns a single user by ID (Admin only)
@@ -77,13 +95,27 @@ func UpdateUserStatus(c *gin.Context) {
 		return
 	}
 
+	before := user
 	user.IsActive = req.IsActive
 
-	if err := config.DB.Save(&user).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("is_active", user.IsActive).Error; err != nil {
+			return err
+		}
+		return services.RecordAudit(tx, currentActor(c), services.AuditUserStatus, services.AuditTargetUser, user.ID, before, user)
+	}); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user status")
 		return
 	}
 
+	// Deactivated users are signed out everywhere
+	if !user.IsActive {
+		if err := services.RevokeAllSessions(user.ID); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke user sessions")
+			return
+		}
+	}
+
 	user.PasswordHash = ""
 	utils.SuccessResponse(c, http.StatusOK, "User status updated successfully", user)
 }
@@ -105,7 +137,27 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	if err := config.DB.Delete(&user).Error; err != nil {
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.RemoveFollows(tx, user.ID); err != nil {
+			return err
+		}
+		if err := services.ClearInterests(tx, user.ID); err != nil {
+			return err
+		}
+		if err := services.RemoveUserLikes(tx, user.ID); err != nil {
+			return err
+		}
+		if err := services.RevokeSessionsTx(tx, user.ID); err != nil {
+			return err
+		}
+		if err := services.RevokeAllAPIKeys(tx, user.ID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		return services.RecordAudit(tx, currentActor(c), services.AuditUserDelete, services.AuditTargetUser, user.ID, user, nil)
+	}); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete user")
 		return
 	}
@@ -117,18 +169,69 @@ func DeleteUser(c *gin.Context) {
 func GetUserStats(c *gin.Context) {
 	var totalUsers int64
 	var totalAdmins int64
+	var totalModerators int64
 	var activeUsers int64
 
 	config.DB.Model(&models.User{}).Count(&totalUsers)
-	config.DB.Model(&models.User{}).Where("role = ?", "admin").Count(&totalAdmins)
+	config.DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&totalAdmins)
+	config.DB.Model(&models.User{}).Where("role = ?", models.RoleModerator).Count(&totalModerators)
 	config.DB.Model(&models.User{}).Where("is_active = ?", true).Count(&activeUsers)
 
 	stats := map[string]interface{}{
-		"total_users":   totalUsers,
-		"total_admins":  totalAdmins,
-		"active_users":  activeUsers,
-		"regular_users": totalUsers - totalAdmins,
+		"total_users":      totalUsers,
+		"total_admins":     totalAdmins,
+		"total_moderators": totalModerators,
+		"active_users":     activeUsers,
+		"regular_users":    totalUsers - totalAdmins - totalModerators,
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "User statistics retrieved successfully", stats)
 }
+
+// GetRoles lists the available roles and their permissions (Admin only)
+func GetRoles(c *gin.Context) {
+	roles := []models.RoleInfo{}
+	for _, role := range []string{models.RoleUser, models.RoleModerator, models.RoleAdmin} {
+		roles = append(roles, models.RoleInfo{Role: role, Permissions: models.RolePermissions[role]})
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Roles retrieved successfully", roles)
+}
+
+// UpdateUserRole assigns a role to a user (Admin only)
+func UpdateUserRole(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, id).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	// Prevent locking yourself out of role management
+	currentUserID, _ := c.Get("userID")
+	if user.ID == currentUserID.(uint) {
+		utils.ErrorResponse(c, http.StatusForbidden, "Cannot change your own role")
+		return
+	}
+
+	before := user
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("role", req.Role).Error; err != nil {
+			return err
+		}
+		return services.RecordAudit(tx, currentActor(c), services.AuditUserRole, services.AuditTargetUser, user.ID, before, user)
+	}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user role")
+		return
+	}
+
+	user.PasswordHash = ""
+	utils.SuccessResponse(c, http.StatusOK, "User role updated successfully", user)
+}
@@ -7,7 +7,9 @@ import (
 	"syscall"
 
 	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/middleware"
 	"ai-of-the-world-backend/routes"
+	"ai-of-the-world-backend/services"
 	"ai-of-the-world-backend/utils"
 
 	"github.com/gin-contrib/cors"
@@ -55,12 +57,15 @@ func main() {
 	corsConfig := cors.Config{
 		AllowOrigins:     config.AppConfig.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
 		AllowCredentials: true,
 	}
 	router.Use(cors.New(corsConfig))
 
+	// Request IDs for log and audit correlation
+	router.Use(middleware.RequestID())
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -69,6 +74,18 @@ func main() {
 		})
 	})
 
+	// Flush buffered view and download counts in the background
+	stopFlusher := make(chan struct{})
+	flusherDone := make(chan struct{})
+	go func() {
+		services.StartCounterFlusher(config.AppConfig.CounterFlushInterval, stopFlusher)
+		close(flusherDone)
+	}()
+
+	// Recompute trending scores and community ranks in the background
+	stopTrending := make(chan struct{})
+	go services.StartTrendingJob(config.AppConfig.TrendingInterval, stopTrending)
+
 	// Setup routes
 	routes.SetupRoutes(router)
 
@@ -82,6 +99,9 @@ func main() {
 	go func() {
 		<-quit
 		log.Println("\n🛑 Shutting down server...")
+		close(stopTrending)
+		close(stopFlusher)
+		<-flusherDone
 		config.CloseDatabase()
 		os.Exit(0)
 	}()
@@ -4,14 +4,24 @@ import (
 	"net/http"
 	"strings"
 
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/services"
 	"ai-of-the-world-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware validates JWT token. Requests may instead authenticate with an
+// X-API-Key header when the route names a scope the key grants; without scopes
+// the route is limited to interactive sessions.
+func AuthMiddleware(scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if rawKey := c.GetHeader("X-API-Key"); rawKey != "" {
+			authenticateAPIKey(c, rawKey, scopes)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "Authorization header required")
@@ -35,22 +45,101 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Set user info in context
-		c.Set("userID", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("email", claims.Email)
-		c.Set("role", claims.Role)
+		// Reject tokens invalidated by a password reset or deactivation
+		if err := services.CheckAccess(claims); err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Session has been revoked")
+			c.Abort()
+			return
+		}
 
+		setUserContext(c, claims)
 		c.Next()
 	}
 }
 
-// AdminMiddleware checks if user is admin
-func AdminMiddleware() gin.HandlerFunc {
+// OptionalAuthMiddleware sets user info when a valid token or read-scoped API key
+// is present but lets anonymous requests through
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rawKey := c.GetHeader("X-API-Key"); rawKey != "" {
+			if key, user, err := services.AuthenticateAPIKey(rawKey); err == nil && key.HasScope(models.ScopeRead) {
+				setAPIKeyContext(c, key, user)
+			}
+			c.Next()
+			return
+		}
+
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := utils.ValidateToken(parts[1]); err == nil && services.CheckAccess(claims) == nil {
+				setUserContext(c, claims)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// setUserContext sets user info in context
+func setUserContext(c *gin.Context, claims *utils.Claims) {
+	c.Set("userID", claims.UserID)
+	c.Set("username", claims.Username)
+	c.Set("email", claims.Email)
+	c.Set("role", claims.Role)
+	c.Set("sessionID", claims.SessionID)
+	c.Set("mfa", claims.MFA)
+}
+
+// authenticateAPIKey authenticates a request by API key, requiring one of scopes
+func authenticateAPIKey(c *gin.Context, rawKey string, scopes []string) {
+	if len(scopes) == 0 {
+		utils.ErrorResponse(c, http.StatusForbidden, "API keys cannot access this endpoint")
+		c.Abort()
+		return
+	}
+
+	key, user, err := services.AuthenticateAPIKey(rawKey)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or revoked API key")
+		c.Abort()
+		return
+	}
+
+	for _, scope := range scopes {
+		if key.HasScope(scope) {
+			setAPIKeyContext(c, key, user)
+			c.Next()
+			return
+		}
+	}
+
+	utils.ErrorResponse(c, http.StatusForbidden, "API key lacks the required scope: "+strings.Join(scopes, " or "))
+	c.Abort()
+}
+
+// setAPIKeyContext sets user info in context for a request made with an API key
+func setAPIKeyContext(c *gin.Context, key *models.APIKey, user *models.User) {
+	c.Set("userID", user.ID)
+	c.Set("username", user.Username)
+	c.Set("email", user.Email)
+	c.Set("role", user.Role)
+	c.Set("apiKeyID", key.ID)
+	c.Set("mfa", false)
+}
+
+// RequirePermission checks that the user's role grants a permission, and, for admins
+// when required, that the session was established with two-factor authentication
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			utils.ErrorResponse(c, http.StatusForbidden, "Admin access required")
+		role := c.GetString("role")
+		if !models.HasPermission(role, permission) {
+			utils.ErrorResponse(c, http.StatusForbidden, "Permission required: "+permission)
+			c.Abort()
+			return
+		}
+
+		if config.AppConfig.RequireAdmin2FA && role == models.RoleAdmin && !c.GetBool("mfa") {
+			utils.ErrorResponse(c, http.StatusForbidden, "Two-factor authentication required for admin access")
 			c.Abort()
 			return
 		}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitStore keeps token buckets. Implementations must be safe for concurrent
+// use; a shared store (e.g. Redis) lets several instances enforce one limit.
+type RateLimitStore interface {
+	// Take removes one token from the bucket for key and reports whether the
+	// request is allowed, how many tokens remain and, if denied, when to retry
+	Take(key string, policy config.RateLimitPolicy, now time.Time) (allowed bool, remaining int, retryAfter time.Duration, err error)
+}
+
+// RateLimiter is the store used by RateLimit; replace it before SetupRoutes to share limits
+var RateLimiter RateLimitStore = NewMemoryRateLimitStore()
+
+// KeyFunc derives the rate limit identity of a request
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP limits each client IP separately
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser limits each authenticated user separately, falling back to the client IP
+func KeyByUser(c *gin.Context) string {
+	if userID, exists := c.Get("userID"); exists {
+		return "user:" + strconv.FormatUint(uint64(userID.(uint)), 10)
+	}
+	return KeyByIP(c)
+}
+
+// KeyByAPIKey limits each authenticated API key separately, falling back to the user
+// or client IP. The key is only known after authentication, so policies applied
+// before it must use KeyByIP; a raw header would let clients pick fresh buckets.
+func KeyByAPIKey(c *gin.Context) string {
+	if keyID, exists := c.Get("apiKeyID"); exists {
+		return "apikey:" + strconv.FormatUint(uint64(keyID.(uint)), 10)
+	}
+	return KeyByUser(c)
+}
+
+// RateLimit throttles requests with the named policy from config.Config.RateLimits.
+// Unknown policies and a disabled limiter let every request through.
+func RateLimit(policyName string, keyFn KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := config.AppConfig.RateLimits[policyName]
+		if !config.AppConfig.RateLimitEnabled || !ok {
+			c.Next()
+			return
+		}
+
+		allowed, remaining, retryAfter, err := RateLimiter.Take(policyName+":"+keyFn(c), policy, time.Now())
+		if err != nil {
+			// Fail open: a broken store shouldn't take the API down
+			log.Println("⚠️  Rate limit store error:", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(policy.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if !allowed {
+			utils.TooManyRequestsResponse(c, retryAfter, "Rate limit exceeded. Please slow down")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bucket is a token bucket refilled continuously at Requests per Per
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryRateLimitStore keeps buckets in process memory; suitable for a single instance
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// memorySweepInterval is how often idle buckets are dropped
+const memorySweepInterval = time.Minute
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, policy config.RateLimitPolicy, now time.Time) (bool, int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	capacity := float64(policy.Requests)
+	rate := capacity / policy.Per.Seconds() // Tokens per second
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, 0, wait, nil
+	}
+
+	b.tokens--
+	return true, int(b.tokens), 0, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again; callers hold mu
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	var longest time.Duration
+	for _, policy := range config.AppConfig.RateLimits {
+		if policy.Per > longest {
+			longest = policy.Per
+		}
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) > longest {
+			delete(s.buckets, key)
+		}
+	}
+}
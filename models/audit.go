@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrAuditImmutable is returned when something tries to change a recorded audit event
+var ErrAuditImmutable = errors.New("audit events are append-only")
+
+// AuditEvent records one admin or moderation action. Events are never updated or deleted.
+type AuditEvent struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	ActorID       uint            `gorm:"not null;index" json:"actor_id"`
+	ActorUsername string          `gorm:"size:100" json:"actor_username"` // Kept even if the actor is later deleted
+	Action        string          `gorm:"size:50;not null;index" json:"action"`
+	TargetType    string          `gorm:"size:30;not null;index:idx_audit_target" json:"target_type"`
+	TargetID      uint            `gorm:"not null;index:idx_audit_target" json:"target_id"`
+	Before        json.RawMessage `gorm:"type:json" json:"before"` // Changed fields before the action
+	After         json.RawMessage `gorm:"type:json" json:"after"`  // Changed fields after the action
+	IPAddress     string          `gorm:"size:45" json:"ip_address"`
+	RequestID     string          `gorm:"size:64;index" json:"request_id"`
+	CreatedAt     time.Time       `gorm:"index" json:"created_at"`
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// BeforeUpdate keeps audit events immutable
+func (AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditImmutable
+}
+
+// BeforeDelete keeps audit events immutable
+func (AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditImmutable
+}
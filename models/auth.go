@@ -1,5 +1,9 @@
 package models
 
+import (
+	"time"
+)
+
 // LoginRequest represents login credentials
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -14,8 +18,16 @@ type RegisterRequest struct {
 	FullName string `json:"full_name" binding:"required,min=1"`
 }
 
+// AuthTokens holds a short-lived access token and its rotating refresh token
+type AuthTokens struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	AuthTokens
+	User User `json:"user"`
 }
@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// Collection visibilities
+const (
+	CollectionPublic   = "public"   // Listed on the owner's profile and on its prompts
+	CollectionUnlisted = "unlisted" // Viewable by anyone with its ID, but never listed
+	CollectionPrivate  = "private"  // Owner and editors only
+)
+
+// Collection editor invitation statuses
+const (
+	EditorInvited  = "invited"
+	EditorAccepted = "accepted"
+)
+
+// Collection is a named, ordered set of prompts of any media kind
+type Collection struct {
+	ID          uint               `gorm:"primaryKey" json:"id"`
+	UserID      uint               `gorm:"not null;index" json:"user_id"`
+	User        User               `gorm:"foreignKey:UserID" json:"-"`
+	Owner       *UserSummary       `gorm:"-" json:"user,omitempty"` // Public view of User
+	Name        string             `gorm:"size:100;not null" json:"name"`
+	Description string             `gorm:"type:text" json:"description"`
+	Visibility  string             `gorm:"type:enum('public','unlisted','private');default:'private';not null" json:"visibility"`
+	ItemsCount  int                `gorm:"default:0;not null" json:"items_count"`
+	Editors     []CollectionEditor `gorm:"foreignKey:CollectionID" json:"editors,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+func (Collection) TableName() string {
+	return "collections"
+}
+
+// CollectionItem places a prompt in a collection
+type CollectionItem struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CollectionID uint      `gorm:"not null;uniqueIndex:idx_collection_item_target;index:idx_collection_item_position" json:"collection_id"`
+	TargetType   string    `gorm:"size:30;not null;uniqueIndex:idx_collection_item_target;index:idx_collection_item_prompt" json:"kind"`
+	TargetID     uint      `gorm:"not null;uniqueIndex:idx_collection_item_target;index:idx_collection_item_prompt" json:"target_id"`
+	Position     int       `gorm:"not null;index:idx_collection_item_position" json:"position"` // 1-based, dense
+	AddedBy      uint      `gorm:"not null" json:"added_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (CollectionItem) TableName() string {
+	return "collection_items"
+}
+
+// CollectionEditor is a user invited to curate someone else's collection
+type CollectionEditor struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	CollectionID uint         `gorm:"not null;uniqueIndex:idx_collection_editor" json:"collection_id"`
+	UserID       uint         `gorm:"not null;uniqueIndex:idx_collection_editor;index" json:"user_id"`
+	User         User         `gorm:"foreignKey:UserID" json:"-"`
+	Editor       *UserSummary `gorm:"-" json:"user,omitempty"` // Public view of User
+	Status       string       `gorm:"type:enum('invited','accepted');default:'invited';not null" json:"status"`
+	InvitedBy    uint         `gorm:"not null" json:"invited_by"`
+	AcceptedAt   *time.Time   `json:"accepted_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+func (CollectionEditor) TableName() string {
+	return "collection_editors"
+}
+
+// CollectionRequest represents the fields of a new or updated collection
+type CollectionRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=2000"`
+	Visibility  string `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
+}
+
+// AddCollectionItemRequest adds a prompt to a collection, at the end unless a position is given
+type AddCollectionItemRequest struct {
+	Kind     string `json:"kind" binding:"required,oneof=image gif video"`
+	ID       uint   `json:"id" binding:"required"`
+	Position int    `json:"position" binding:"omitempty,min=1"`
+}
+
+// MoveCollectionItemRequest moves an item to a new 1-based position
+type MoveCollectionItemRequest struct {
+	Position int `json:"position" binding:"required,min=1"`
+}
+
+// InviteEditorRequest invites a user to edit a collection
+type InviteEditorRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// CollectionEntry is an item of a collection with its prompt
+type CollectionEntry struct {
+	ItemID   uint        `json:"item_id"`
+	Position int         `json:"position"`
+	Kind     string      `json:"kind"`
+	AddedBy  uint        `json:"added_by"`
+	AddedAt  time.Time   `json:"added_at"`
+	Prompt   MediaPrompt `json:"prompt"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Comment is a threaded comment on a prompt of any media kind
+type Comment struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	TargetType   string           `gorm:"size:30;not null;index:idx_comment_target" json:"kind"` // Media kind name
+	TargetID     uint             `gorm:"not null;index:idx_comment_target" json:"target_id"`
+	ParentID     *uint            `gorm:"index" json:"parent_id"` // Nil for top-level comments
+	UserID       uint             `gorm:"not null;index" json:"user_id"`
+	User         User             `gorm:"foreignKey:UserID" json:"-"`
+	Author       *UserSummary     `gorm:"-" json:"user,omitempty"` // Public view of User
+	Body         string           `gorm:"type:text;not null" json:"body"`
+	RepliesCount int              `gorm:"default:0;not null" json:"replies_count"` // Direct replies, including deleted ones
+	Mentions     []CommentMention `gorm:"foreignKey:CommentID" json:"mentions"`
+	EditedAt     *time.Time       `json:"edited_at"`
+	DeletedAt    *time.Time       `gorm:"index" json:"deleted_at"` // Soft deleted comments keep their place in the thread
+	DeletedBy    *uint            `json:"-"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// CommentMention links a comment to a user it mentions with @username
+type CommentMention struct {
+	ID        uint   `gorm:"primaryKey" json:"-"`
+	CommentID uint   `gorm:"not null;uniqueIndex:idx_mention_comment_user" json:"-"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_mention_comment_user;index" json:"user_id"`
+	Username  string `gorm:"size:100;not null" json:"username"`
+}
+
+func (CommentMention) TableName() string {
+	return "comment_mentions"
+}
+
+// CreateCommentRequest represents a new comment or reply
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required,max=2000"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// UpdateCommentRequest represents an edit of a comment's text
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Follow records that one user follows another
+type Follow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FollowerID uint      `gorm:"not null;uniqueIndex:idx_follow_pair" json:"follower_id"`
+	FolloweeID uint      `gorm:"not null;uniqueIndex:idx_follow_pair;index" json:"followee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (Follow) TableName() string {
+	return "follows"
+}
+
+// FollowStatus reports the caller's follow state of a user after a follow or unfollow
+type FollowStatus struct {
+	Following      bool `json:"following"`
+	FollowersCount int  `json:"followers_count"`
+}
+
+// UserSummary is the public view of a user shown in lists
+type UserSummary struct {
+	ID                uint   `json:"id"`
+	Username          string `json:"username"`
+	FullName          string `json:"full_name"`
+	ProfilePictureURL string `json:"profile_picture_url"`
+	IsVerified        bool   `json:"is_verified"`
+}
+
+// PublicProfile is the public view of a user's profile
+type PublicProfile struct {
+	UserSummary
+	Bio            string    `json:"bio"`
+	TotalCreations int       `json:"total_creations"`
+	TotalLikes     int       `json:"total_likes"`
+	TrendingScore  int       `json:"trending_score"`
+	CommunityRank  *int      `json:"community_rank"`
+	FollowersCount int       `json:"followers_count"`
+	FollowingCount int       `json:"following_count"`
+	FollowedByMe   bool      `json:"followed_by_me"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// FollowEntry is an entry of a followers or following list
+type FollowEntry struct {
+	User       UserSummary `json:"user"`
+	FollowedAt time.Time   `json:"followed_at"`
+}
+
+// FeedItem is an entry of a merged feed of every media kind
+type FeedItem struct {
+	Kind        string      `json:"kind"`
+	PublishedAt time.Time   `json:"published_at"`
+	Prompt      MediaPrompt `json:"prompt"`
+}
+
+// RecommendedPrompt is an entry of the for-you feed or a similar-prompt listing
+type RecommendedPrompt struct {
+	Kind   string      `json:"kind"`
+	Score  float64     `json:"score"`
+	Prompt MediaPrompt `json:"prompt"`
+}
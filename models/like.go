@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Like records that a user liked a prompt of any media kind
+type Like struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_like_user_target" json:"user_id"`
+	TargetType string    `gorm:"size:30;not null;uniqueIndex:idx_like_user_target;index:idx_like_target" json:"kind"` // Media kind name
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_like_user_target;index:idx_like_target" json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (Like) TableName() string {
+	return "likes"
+}
+
+// LikeStatus reports the caller's like state of a prompt after a like or unlike
+type LikeStatus struct {
+	Liked      bool `json:"liked"`
+	LikesCount int  `json:"likes_count"`
+}
+
+// LikedPrompt is an entry of a user's likes listing
+type LikedPrompt struct {
+	Kind    string      `json:"kind"`
+	LikedAt time.Time   `json:"liked_at"`
+	Prompt  MediaPrompt `json:"prompt"`
+}
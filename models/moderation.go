@@ -0,0 +1,142 @@
+package models
+
+import "time"
+
+// ModerationNote is an internal note moderators attach to a prompt; owners never see it
+type ModerationNote struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	TargetType     string    `gorm:"size:30;not null;index:idx_moderation_note_target" json:"target_type"` // Media kind name
+	TargetID       uint      `gorm:"not null;index:idx_moderation_note_target" json:"target_id"`
+	AuthorID       uint      `gorm:"not null" json:"author_id"`
+	AuthorUsername string    `gorm:"size:100" json:"author_username"`
+	Note           string    `gorm:"type:text;not null" json:"note"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (ModerationNote) TableName() string {
+	return "moderation_notes"
+}
+
+// RejectPromptRequest represents the request body for rejecting a prompt
+type RejectPromptRequest struct {
+	Reason  string `json:"reason" binding:"required"`  // One of the configured rejection reasons
+	Details string `json:"details" binding:"max=2000"` // Free text shown to the owner
+	Note    string `json:"note" binding:"max=2000"`    // Internal note for moderators only
+}
+
+// CreateModerationNoteRequest represents the request body for adding a moderator note
+type CreateModerationNoteRequest struct {
+	Note string `json:"note" binding:"required,max=2000"`
+}
+
+// Moderation history actions
+const (
+	ModerationSubmit   = "submit"
+	ModerationApprove  = "approve"
+	ModerationReject   = "reject"
+	ModerationResubmit = "resubmit"
+)
+
+// ModerationEvent is one step in a prompt's review history: a submission, a
+// decision or a resubmission after rejection
+type ModerationEvent struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TargetType    string    `gorm:"size:30;not null;index:idx_moderation_event_target" json:"target_type"` // Media kind name
+	TargetID      uint      `gorm:"not null;index:idx_moderation_event_target" json:"target_id"`
+	ActorID       uint      `gorm:"not null" json:"actor_id"`
+	ActorUsername string    `gorm:"size:100" json:"actor_username"`
+	Action        string    `gorm:"size:20;not null" json:"action"`
+	FromStatus    string    `gorm:"size:20" json:"from_status"`
+	ToStatus      string    `gorm:"size:20;not null" json:"to_status"`
+	Reason        string    `gorm:"type:text" json:"reason"`
+	Details       string    `gorm:"type:text" json:"details"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (ModerationEvent) TableName() string {
+	return "moderation_events"
+}
+
+// BulkItem identifies one prompt of any media kind
+type BulkItem struct {
+	Kind string `json:"kind" binding:"required,oneof=image gif video"`
+	ID   uint   `json:"id" binding:"required"`
+}
+
+// BulkModerationRequest represents the request body for a bulk moderation action
+type BulkModerationRequest struct {
+	Action  string     `json:"action" binding:"required,oneof=approve reject publish unpublish feature unfeature delete"`
+	Items   []BulkItem `json:"items" binding:"required,min=1,max=100,dive"`
+	Reason  string     `json:"reason"`                     // Required for reject
+	Details string     `json:"details" binding:"max=2000"` // Reject only
+	Note    string     `json:"note" binding:"max=2000"`    // Reject only
+}
+
+// BulkItemResult reports the outcome of a bulk action on one prompt
+type BulkItemResult struct {
+	Kind    string `json:"kind"`
+	ID      uint   `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkModerationResponse is the per-item report of a bulk moderation action
+type BulkModerationResponse struct {
+	Action    string           `json:"action"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// ModerationClaim locks a pending prompt to one moderator until it expires or a decision is made
+type ModerationClaim struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	TargetType        string    `gorm:"size:30;not null;uniqueIndex:idx_moderation_claim_target" json:"kind"`
+	TargetID          uint      `gorm:"not null;uniqueIndex:idx_moderation_claim_target" json:"target_id"`
+	ModeratorID       uint      `gorm:"not null;index" json:"moderator_id"`
+	ModeratorUsername string    `gorm:"size:100" json:"moderator_username"`
+	ExpiresAt         time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func (ModerationClaim) TableName() string {
+	return "moderation_claims"
+}
+
+// QueueItem is a pending prompt of any media kind in the moderation queue
+type QueueItem struct {
+	Kind              string     `json:"kind"`
+	ID                uint       `json:"id"`
+	UserID            uint       `json:"user_id"`
+	ProjectTitle      string     `json:"project_title"`
+	CreatedAt         time.Time  `json:"created_at"`
+	AgeSeconds        int64      `gorm:"-" json:"age_seconds"`
+	ClaimedBy         *uint      `json:"claimed_by"`
+	ClaimedByUsername *string    `json:"claimed_by_username"`
+	ClaimExpiresAt    *time.Time `json:"claim_expires_at"`
+}
+
+// ClaimRequest represents the request body for claiming a queue item
+type ClaimRequest struct {
+	Kind string `json:"kind" binding:"required,oneof=image gif video"`
+	ID   uint   `json:"id" binding:"required"`
+}
+
+// ModeratorThroughput counts one moderator's decisions in the stats window
+type ModeratorThroughput struct {
+	ModeratorID uint   `json:"moderator_id"`
+	Username    string `json:"username"`
+	Approved    int64  `json:"approved"`
+	Rejected    int64  `json:"rejected"`
+	Total       int64  `json:"total"`
+}
+
+// QueueStats summarises the moderation queue
+type QueueStats struct {
+	Size             int64                 `json:"size"`
+	SizeByKind       map[string]int64      `json:"size_by_kind"`
+	Claimed          int64                 `json:"claimed"`
+	OldestAgeSeconds int64                 `json:"oldest_age_seconds"`
+	WindowHours      float64               `json:"window_hours"`
+	Throughput       []ModeratorThroughput `json:"throughput"`
+}
@@ -12,6 +12,7 @@ type OTP struct {
 	Purpose   string    `gorm:"type:enum('signup','forgot_password');not null" json:"purpose"`
 	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
 	Verified  bool      `gorm:"default:false" json:"verified"`
+	Attempts  int       `gorm:"default:0;not null" json:"-"` // Failed verification attempts
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -22,13 +23,14 @@ func (OTP) TableName() string {
 // SendOTPRequest represents the request to send an OTP
 type SendOTPRequest struct {
 	Email   string `json:"email" binding:"required,email"`
-	Purpose string `json:"purpose" binding:"required,oneof=signup forgot_password"`
+	Purpose string `json:"purpose" binding:"omitempty,oneof=signup forgot_password"` // Optional; any purpose when empty
 }
 
 // VerifyOTPRequest represents the request to verify an OTP
 type VerifyOTPRequest struct {
-	Email string `json:"email" binding:"required,email"`
-	OTP   string `json:"otp" binding:"required,len=6"`
+	Email   string `json:"email" binding:"required,email"`
+	OTP     string `json:"otp" binding:"required,len=6"`
+	Purpose string `json:"purpose" binding:"required,oneof=signup forgot_password"`
 }
 
 // SignupWithOTPRequest represents the complete signup request with OTP
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Report reasons
+const (
+	ReportSpam       = "spam"
+	ReportNSFW       = "nsfw"
+	ReportStolen     = "stolen_work"
+	ReportHarassment = "harassment"
+	ReportOther      = "other"
+)
+
+// Report statuses
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusDismissed = "dismissed"
+	ReportStatusActioned  = "actioned"
+)
+
+// Report is a community flag raised against a prompt of any media kind
+type Report struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	TargetType string     `gorm:"size:30;not null;uniqueIndex:idx_report_reporter_target;index:idx_report_target" json:"kind"` // Media kind name
+	TargetID   uint       `gorm:"not null;uniqueIndex:idx_report_reporter_target;index:idx_report_target" json:"target_id"`
+	ReporterID uint       `gorm:"not null;uniqueIndex:idx_report_reporter_target" json:"reporter_id"`
+	Reason     string     `gorm:"type:enum('spam','nsfw','stolen_work','harassment','other');not null;index" json:"reason"`
+	Details    string     `gorm:"type:text" json:"details"`
+	Status     string     `gorm:"type:enum('open','dismissed','actioned');default:'open';not null;index" json:"status"`
+	Resolution string     `gorm:"size:20" json:"resolution"` // Triage action taken
+	ResolvedBy *uint      `json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (Report) TableName() string {
+	return "reports"
+}
+
+// CreateReportRequest represents the request body for reporting a prompt
+type CreateReportRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam nsfw stolen_work harassment other"`
+	Details string `json:"details" binding:"max=2000"`
+}
+
+// ResolveReportRequest represents the request body for triaging a report
+type ResolveReportRequest struct {
+	Action  string `json:"action" binding:"required,oneof=dismiss approve reject publish unpublish"`
+	Reason  string `json:"reason"`                     // Rejection reason, required for reject
+	Details string `json:"details" binding:"max=2000"` // Reject only
+	Note    string `json:"note" binding:"max=2000"`    // Internal moderator note, reject only
+}
+
+// ReportResolution reports the outcome of triaging a report
+type ReportResolution struct {
+	Action   string `json:"action"`
+	Status   string `json:"status"`
+	Resolved int64  `json:"resolved"` // Open reports on the same prompt closed by this decision
+}
@@ -0,0 +1,65 @@
+package models
+
+// User roles
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions granted by roles
+const (
+	PermPromptsModerate  = "prompts.moderate"  // Review, approve, reject, publish and unpublish prompts
+	PermPromptsManage    = "prompts.manage"    // Edit, feature and delete any prompt
+	PermTagsManage       = "tags.manage"       // Create, update and delete tags
+	PermUsersManage      = "users.manage"      // View, deactivate, sign out and delete users
+	PermRolesAssign      = "roles.assign"      // Change user roles
+	PermAuditView        = "audit.view"        // Read the audit log
+	PermCommentsModerate = "comments.moderate" // Delete any comment
+)
+
+// RolePermissions maps each role to the permissions it grants
+var RolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermPromptsModerate,
+		PermTagsManage,
+		PermCommentsModerate,
+	},
+	RoleAdmin: {
+		PermPromptsModerate,
+		PermPromptsManage,
+		PermTagsManage,
+		PermUsersManage,
+		PermRolesAssign,
+		PermAuditView,
+		PermCommentsModerate,
+	},
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether role grants permission
+func HasPermission(role, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateRoleRequest represents the request body for assigning a role
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// RoleInfo describes a role and its permissions
+type RoleInfo struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
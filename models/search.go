@@ -0,0 +1,33 @@
+package models
+
+// SearchResult is a prompt matched by a search
+type SearchResult struct {
+	Kind   string      `json:"kind"`
+	Score  float64     `json:"score"` // Full-text relevance; 0 without a query
+	Prompt MediaPrompt `json:"prompt"`
+}
+
+// TagFacet counts the matching prompts carrying a tag
+type TagFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// ModelFacet counts the matching prompts made with a model or tool
+type ModelFacet struct {
+	ModelOrTool string `json:"model_or_tool"`
+	Count       int64  `json:"count"`
+}
+
+// SearchFacets summarizes every matching prompt, not just the returned page
+type SearchFacets struct {
+	Tags   []TagFacet   `json:"tags"`
+	Models []ModelFacet `json:"models"`
+}
+
+// SearchResponse is a page of search results with facet counts
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+	Facets  SearchFacets   `json:"facets"`
+}
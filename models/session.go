@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// Session represents a login; every refresh token issued for the login belongs to it
+type Session struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Device      string     `gorm:"size:100" json:"device"`
+	IPAddress   string     `gorm:"size:45" json:"ip_address"`
+	UserAgent   string     `gorm:"size:500" json:"user_agent"`
+	LastSeenAt  time.Time  `json:"last_seen_at"`
+	ExpiresAt   time.Time  `gorm:"not null;index" json:"expires_at"`
+	MFAVerified bool       `gorm:"default:false" json:"mfa_verified"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Current     bool       `gorm:"-" json:"current"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// SessionClient describes the client a session is opened from
+type SessionClient struct {
+	IPAddress string
+	UserAgent string
+}
+
+// RefreshToken represents one rotation of a session's refresh token
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	SessionID uint       `gorm:"not null;index" json:"session_id"`
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// RefreshTokenRequest represents a request carrying a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -26,6 +26,11 @@ type CreateTagRequest struct {
 	Description string `json:"description"`
 }
 
+// UpdateInterestsRequest replaces a user's interests with a list of tag IDs
+type UpdateInterestsRequest struct {
+	TagIDs []uint `json:"tag_ids" binding:"required,max=20"`
+}
+
 // UpdateTagRequest represents the request body for updating a tag
 type UpdateTagRequest struct {
 	Name        string `json:"name" binding:"omitempty,min=1,max=100"`
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// AuthThrottle tracks failed authentication attempts for an account or IP address
+type AuthThrottle struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ThrottleKey   string     `gorm:"uniqueIndex;size:255;not null" json:"throttle_key"` // e.g. "login:account:jane@example.com"
+	Failures      int        `gorm:"default:0;not null" json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (AuthThrottle) TableName() string {
+	return "auth_throttles"
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Score windows
+const (
+	WindowDaily   = "daily"
+	WindowWeekly  = "weekly"
+	WindowAllTime = "all_time"
+)
+
+// ScoreWindows lists the windows trending scores are computed for
+var ScoreWindows = []string{WindowDaily, WindowWeekly, WindowAllTime}
+
+// IsScoreWindow reports whether a window name is known
+func IsScoreWindow(window string) bool {
+	for _, w := range ScoreWindows {
+		if w == window {
+			return true
+		}
+	}
+	return false
+}
+
+// EngagementDay buckets a prompt's views and downloads by UTC day so scores can be
+// computed over a window; likes are read from the likes table
+type EngagementDay struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TargetType string    `gorm:"size:30;not null;uniqueIndex:idx_engagement_target_day" json:"kind"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_engagement_target_day" json:"target_id"`
+	Day        time.Time `gorm:"type:date;not null;uniqueIndex:idx_engagement_target_day;index" json:"day"`
+	Views      int       `gorm:"default:0;not null" json:"views"`
+	Downloads  int       `gorm:"default:0;not null" json:"downloads"`
+}
+
+func (EngagementDay) TableName() string {
+	return "engagement_days"
+}
+
+// TrendingScore is a prompt's computed score and rank in one window
+type TrendingScore struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	Window     string    `gorm:"column:score_window;size:20;not null;uniqueIndex:idx_trending_window_target;index:idx_trending_window_rank" json:"window"`
+	TargetType string    `gorm:"size:30;not null;uniqueIndex:idx_trending_window_target" json:"kind"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_trending_window_target" json:"target_id"`
+	UserID     uint      `gorm:"not null" json:"user_id"`
+	Score      float64   `gorm:"not null" json:"score"`
+	Rank       int       `gorm:"column:score_rank;not null;index:idx_trending_window_rank" json:"rank"`
+	ComputedAt time.Time `gorm:"not null" json:"computed_at"`
+}
+
+func (TrendingScore) TableName() string {
+	return "trending_scores"
+}
+
+// CreatorScore is a creator's summed prompt scores and rank in one window
+type CreatorScore struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	Window     string    `gorm:"column:score_window;size:20;not null;uniqueIndex:idx_creator_window_user;index:idx_creator_window_rank" json:"window"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_creator_window_user" json:"user_id"`
+	Score      float64   `gorm:"not null" json:"score"`
+	Rank       int       `gorm:"column:score_rank;not null;index:idx_creator_window_rank" json:"rank"`
+	ComputedAt time.Time `gorm:"not null" json:"computed_at"`
+}
+
+func (CreatorScore) TableName() string {
+	return "creator_scores"
+}
+
+// TrendingPrompt is an entry of the trending listing
+type TrendingPrompt struct {
+	Rank   int         `json:"rank"`
+	Score  float64     `json:"score"`
+	Kind   string      `json:"kind"`
+	Prompt MediaPrompt `json:"prompt"`
+}
+
+// LeaderboardEntry is a creator's row on the leaderboard
+type LeaderboardEntry struct {
+	Rank              int     `json:"rank"`
+	Score             float64 `json:"score"`
+	UserID            uint    `json:"user_id"`
+	Username          string  `json:"username"`
+	FullName          string  `json:"full_name"`
+	ProfilePictureURL string  `json:"profile_picture_url"`
+	IsVerified        bool    `json:"is_verified"`
+	TotalCreations    int     `json:"total_creations"`
+	TotalLikes        int     `json:"total_likes"`
+}
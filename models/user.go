@@ -10,17 +10,23 @@ type User struct {
 	Email             string     `gorm:"uniqueIndex;size:255;not null" json:"email"`
 	PasswordHash      string     `gorm:"size:255;not null" json:"-"`
 	FullName          string     `gorm:"size:255" json:"full_name"`
-	Role              string     `gorm:"type:enum('user','admin');default:'user';not null" json:"role"`
+	Role              string     `gorm:"type:enum('user','moderator','admin');default:'user';not null" json:"role"`
 	ProfilePictureURL string     `gorm:"size:500" json:"profile_picture_url"`
 	Bio               string     `gorm:"type:text" json:"bio"`
-	Interests         string     `gorm:"type:text" json:"interests"` // JSON array of interest IDs
+	Interests         []Tag      `gorm:"many2many:user_interests;" json:"interests,omitempty"`
 	TotalCreations    int        `gorm:"default:0" json:"total_creations"`
 	TotalLikes        int        `gorm:"default:0" json:"total_likes"`
 	TrendingScore     int        `gorm:"default:0" json:"trending_score"`
 	CommunityRank     *int       `json:"community_rank"`
+	FollowersCount    int        `gorm:"default:0" json:"followers_count"`
+	FollowingCount    int        `gorm:"default:0" json:"following_count"`
 	IsVerified        bool       `gorm:"default:false" json:"is_verified"`
 	IsActive          bool       `gorm:"default:true" json:"is_active"`
 	EmailVerified     bool       `gorm:"default:false" json:"email_verified"`
+	TokenVersion      int        `gorm:"default:0;not null" json:"-"` // Bumped to invalidate every issued token
+	TOTPSecret        string     `gorm:"column:totp_secret;size:64" json:"-"`
+	TOTPEnabled       bool       `gorm:"column:totp_enabled;default:false" json:"-"` // Only shown to the user, via ProfileResponse
+	TOTPLastStep      int64      `gorm:"column:totp_last_step;default:0" json:"-"`   // Last accepted time step, to reject replays
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	LastLogin         *time.Time `json:"last_login"`
@@ -29,3 +35,9 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// ProfileResponse is the caller's own profile, including private account settings
+type ProfileResponse struct {
+	User
+	TOTPEnabled bool `json:"totp_enabled"`
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"ai-of-the-world-backend/controllers"
 	"ai-of-the-world-backend/middleware"
+	"ai-of-the-world-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,15 +12,19 @@ import (
 func SetupRoutes(router *gin.Engine) {
 	// API v1 group
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.RateLimit("api", middleware.KeyByIP))
 	{
 		// Public routes
 		auth := v1.Group("/auth")
+		auth.Use(middleware.RateLimit("auth", middleware.KeyByIP))
 		{
 			auth.POST("/register", controllers.Register)
 			auth.POST("/login", controllers.Login)
+			auth.POST("/login/2fa", controllers.VerifyLoginMFA)
+			auth.POST("/refresh", controllers.RefreshToken)
 
 			// OTP routes
-			auth.POST("/send-otp", controllers.SendOTP)
+			auth.POST("/send-otp", middleware.RateLimit("otp", middleware.KeyByIP), controllers.SendOTP)
 			auth.POST("/verify-otp", controllers.VerifyOTP)
 			auth.POST("/signup-with-otp", controllers.SignupWithOTP)
 			auth.POST("/reset-password", controllers.ResetPassword)
@@ -34,81 +39,232 @@ func SetupRoutes(router *gin.Engine) {
 			tags.GET("/stats", controllers.GetTagStats)
 		}
 
-		// Public image prompts (read-only)
+		// Trending prompts and creator leaderboard
+		v1.GET("/trending", middleware.OptionalAuthMiddleware(), controllers.GetTrending)
+		v1.GET("/leaderboard", controllers.GetLeaderboard)
+
+		// Full-text search across every media kind
+		v1.GET("/search", middleware.OptionalAuthMiddleware(), controllers.Search)
+
+		// Comment threads (read-only)
+		v1.GET("/comments/:id/replies", middleware.OptionalAuthMiddleware(), controllers.GetCommentReplies)
+
+		// Public collections (read-only; private ones only for their owner and editors)
+		collections := v1.Group("")
+		collections.Use(middleware.OptionalAuthMiddleware())
+		{
+			collections.GET("/collections/:id", controllers.GetCollection)
+			collections.GET("/collections/:id/items", controllers.GetCollectionItems)
+			collections.GET("/users/:id/collections", controllers.GetUserCollections)
+		}
+
+		// Public profiles and the social graph
+		users := v1.Group("/users")
+		users.Use(middleware.OptionalAuthMiddleware())
+		{
+			users.GET("/:id", controllers.GetPublicProfile)
+			users.GET("/:id/followers", controllers.GetFollowers)
+			users.GET("/:id/following", controllers.GetFollowing)
+		}
+
+		// Public image prompts (read-only; approved and published, plus the caller's own)
 		images := v1.Group("/images")
+		images.Use(middleware.OptionalAuthMiddleware())
 		{
-			images.GET("", controllers.GetImagePrompts)
-			images.GET("/:id", controllers.GetImagePromptByID)
+			images.GET("", controllers.Images.List)
+			images.GET("/:id", controllers.Images.Get)
+			images.GET("/:id/download", controllers.Images.Download)
+			images.GET("/:id/comments", controllers.Images.GetComments)
+			images.GET("/:id/collections", controllers.Images.GetCollections)
+			images.GET("/:id/similar", controllers.Images.GetSimilar)
 		}
 
-		// Public GIF prompts (read-only)
+		// Public GIF prompts (read-only; approved and published, plus the caller's own)
 		gifs := v1.Group("/gifs")
+		gifs.Use(middleware.OptionalAuthMiddleware())
 		{
-			gifs.GET("", controllers.GetGIFPrompts)
-			gifs.GET("/:id", controllers.GetGIFPromptByID)
+			gifs.GET("", controllers.GIFs.List)
+			gifs.GET("/:id", controllers.GIFs.Get)
+			gifs.GET("/:id/download", controllers.GIFs.Download)
+			gifs.GET("/:id/comments", controllers.GIFs.GetComments)
+			gifs.GET("/:id/collections", controllers.GIFs.GetCollections)
+			gifs.GET("/:id/similar", controllers.GIFs.GetSimilar)
 		}
 
-		// Public video prompts (read-only)
+		// Public video prompts (read-only; approved and published, plus the caller's own)
 		videos := v1.Group("/videos")
+		videos.Use(middleware.OptionalAuthMiddleware())
+		{
+			videos.GET("", controllers.Videos.List)
+			videos.GET("/:id", controllers.Videos.Get)
+			videos.GET("/:id/download", controllers.Videos.Download)
+			videos.GET("/:id/comments", controllers.Videos.GetComments)
+			videos.GET("/:id/collections", controllers.Videos.GetCollections)
+			videos.GET("/:id/similar", controllers.Videos.GetSimilar)
+		}
+
+		// Programmatic routes (Bearer token, or an API key with the route's scope)
+		v1.GET("/profile", middleware.AuthMiddleware(models.ScopeRead), controllers.GetProfile)
+
+		uploads := v1.Group("")
+		uploads.Use(middleware.AuthMiddleware(models.ScopeUpload), middleware.RateLimit("upload", middleware.KeyByAPIKey))
+		{
+			uploads.POST("/images/upload", controllers.Images.Upload)
+			uploads.PUT("/images/:id/resubmit", controllers.Images.Resubmit)
+			uploads.POST("/gifs/upload", controllers.GIFs.Upload)
+			uploads.PUT("/gifs/:id/resubmit", controllers.GIFs.Resubmit)
+			uploads.POST("/videos/upload", controllers.Videos.Upload)
+			uploads.PUT("/videos/:id/resubmit", controllers.Videos.Resubmit)
+		}
+
+		deletes := v1.Group("")
+		deletes.Use(middleware.AuthMiddleware(models.ScopeDelete))
 		{
-			videos.GET("", controllers.GetVideoPrompts)
-			videos.GET("/:id", controllers.GetVideoPromptByID)
+			deletes.DELETE("/images/:id", controllers.Images.Delete)
+			deletes.DELETE("/gifs/:id", controllers.GIFs.Delete)
+			deletes.DELETE("/videos/:id", controllers.Videos.Delete)
 		}
 
-		// Protected routes (require authentication)
+		// Protected routes (require an interactive session)
 		protected := v1.Group("")
 		protected.Use(middleware.AuthMiddleware())
 		{
+			// Session
+			protected.POST("/auth/logout", controllers.Logout)
+
 			// User profile
-			protected.GET("/profile", controllers.GetProfile)
+			protected.GET("/profile/interests", controllers.GetInterests)
 			protected.PUT("/profile/interests", controllers.UpdateInterests)
+			protected.GET("/profile/sessions", controllers.GetMySessions)
+			protected.DELETE("/profile/sessions", controllers.RevokeMyOtherSessions)
+			protected.DELETE("/profile/sessions/:id", controllers.RevokeMySession)
+
+			// Two-factor authentication
+			protected.POST("/profile/2fa/setup", controllers.SetupTOTP)
+			protected.POST("/profile/2fa/enable", controllers.EnableTOTP)
+			protected.POST("/profile/2fa/disable", controllers.DisableTOTP)
+			protected.POST("/profile/2fa/recovery-codes", controllers.RegenerateRecoveryCodes)
+
+			// Reports
+			protected.POST("/images/:id/report", controllers.Images.Report)
+			protected.POST("/gifs/:id/report", controllers.GIFs.Report)
+			protected.POST("/videos/:id/report", controllers.Videos.Report)
+
+			// Comments
+			comments := protected.Group("", middleware.RateLimit("comment", middleware.KeyByUser))
+			comments.POST("/images/:id/comments", controllers.Images.CreateComment)
+			comments.POST("/gifs/:id/comments", controllers.GIFs.CreateComment)
+			comments.POST("/videos/:id/comments", controllers.Videos.CreateComment)
+			protected.PUT("/comments/:id", controllers.UpdateComment)
+			protected.DELETE("/comments/:id", controllers.DeleteComment)
+
+			// Collections
+			protected.GET("/profile/collections", controllers.GetMyCollections)
+			protected.GET("/profile/collection-invites", controllers.GetMyCollectionInvites)
+			protected.POST("/collections", controllers.CreateCollection)
+			protected.PUT("/collections/:id", controllers.UpdateCollection)
+			protected.DELETE("/collections/:id", controllers.DeleteCollection)
+			protected.POST("/collections/:id/items", controllers.AddCollectionItem)
+			protected.PUT("/collections/:id/items/:itemId/position", controllers.MoveCollectionItem)
+			protected.DELETE("/collections/:id/items/:itemId", controllers.RemoveCollectionItem)
+			protected.POST("/collections/:id/editors", controllers.InviteCollectionEditor)
+			protected.POST("/collections/:id/editors/accept", controllers.AcceptCollectionInvite)
+			protected.DELETE("/collections/:id/editors/:userId", controllers.RemoveCollectionEditor)
 
-			// Image upload
-			protected.POST("/images/upload", controllers.UploadImage)
-			protected.DELETE("/images/:id", controllers.DeleteImagePrompt)
+			// Follows and feeds
+			protected.POST("/users/:id/follow", controllers.FollowUser)
+			protected.DELETE("/users/:id/follow", controllers.UnfollowUser)
+			protected.GET("/feed/following", controllers.GetFollowingFeed)
+			protected.GET("/feed/for-you", controllers.GetForYouFeed)
 
-			// GIF upload
-			protected.POST("/gifs/upload", controllers.UploadGIF)
-			protected.DELETE("/gifs/:id", controllers.DeleteGIFPrompt)
+			// Likes
+			protected.POST("/images/:id/like", controllers.Images.Like)
+			protected.DELETE("/images/:id/like", controllers.Images.Unlike)
+			protected.POST("/gifs/:id/like", controllers.GIFs.Like)
+			protected.DELETE("/gifs/:id/like", controllers.GIFs.Unlike)
+			protected.POST("/videos/:id/like", controllers.Videos.Like)
+			protected.DELETE("/videos/:id/like", controllers.Videos.Unlike)
+			protected.GET("/profile/likes", controllers.GetMyLikes)
 
-			// Video upload
-			protected.POST("/videos/upload", controllers.UploadVideo)
-			protected.DELETE("/videos/:id", controllers.DeleteVideoPrompt)
+			// API keys
+			protected.GET("/profile/api-keys", controllers.GetMyAPIKeys)
+			protected.POST("/profile/api-keys", controllers.CreateAPIKey)
+			protected.DELETE("/profile/api-keys/:id", controllers.RevokeAPIKey)
 
-			// Admin routes
+			// Staff routes, each gated by the permission it needs
 			admin := protected.Group("/admin")
-			admin.Use(middleware.AdminMiddleware())
 			{
 				// Tag management
-				admin.POST("/tags", controllers.CreateTag)
-				admin.PUT("/tags/:id", controllers.UpdateTag)
-				admin.DELETE("/tags/:id", controllers.DeleteTag)
+				tagAdmin := admin.Group("/tags", middleware.RequirePermission(models.PermTagsManage))
+				tagAdmin.POST("", controllers.CreateTag)
+				tagAdmin.PUT("/:id", controllers.UpdateTag)
+				tagAdmin.DELETE("/:id", controllers.DeleteTag)
 
 				// User management
-				admin.GET("/users/stats", controllers.GetUserStats)
-				admin.GET("/users", controllers.GetAllUsers)
-				admin.GET("/users/:id", controllers.GetUserByID)
-				admin.PUT("/users/:id/status", controllers.UpdateUserStatus)
-				admin.DELETE("/users/:id", controllers.DeleteUser)
-
-				// Image management
-				admin.PUT("/images/:id/approve", controllers.ApproveImagePrompt)
-				admin.PUT("/images/:id/reject", controllers.RejectImagePrompt)
-				admin.PUT("/images/:id/publish", controllers.PublishImagePrompt)
-				admin.PUT("/images/:id/unpublish", controllers.UnpublishImagePrompt)
-				admin.PUT("/images/:id", controllers.UpdateImagePrompt)
-
-				// GIF management
-				admin.PUT("/gifs/:id/approve", controllers.ApproveGIFPrompt)
-				admin.PUT("/gifs/:id/reject", controllers.RejectGIFPrompt)
-				admin.PUT("/gifs/:id/publish", controllers.PublishGIFPrompt)
-				admin.PUT("/gifs/:id/unpublish", controllers.UnpublishGIFPrompt)
-
-				// Video management
-				admin.PUT("/videos/:id/approve", controllers.ApproveVideoPrompt)
-				admin.PUT("/videos/:id/reject", controllers.RejectVideoPrompt)
-				admin.PUT("/videos/:id/publish", controllers.PublishVideoPrompt)
-				admin.PUT("/videos/:id/unpublish", controllers.UnpublishVideoPrompt)
+				userAdmin := admin.Group("/users", middleware.RequirePermission(models.PermUsersManage))
+				userAdmin.GET("/stats", controllers.GetUserStats)
+				userAdmin.GET("", controllers.GetAllUsers)
+				userAdmin.GET("/:id", controllers.GetUserByID)
+				userAdmin.PUT("/:id/status", controllers.UpdateUserStatus)
+				userAdmin.GET("/:id/sessions", controllers.GetUserSessions)
+				userAdmin.DELETE("/:id/sessions", controllers.RevokeUserSessions)
+				userAdmin.DELETE("/:id", controllers.DeleteUser)
+
+				// Role assignment
+				roleAdmin := admin.Group("", middleware.RequirePermission(models.PermRolesAssign))
+				roleAdmin.GET("/roles", controllers.GetRoles)
+				roleAdmin.PUT("/users/:id/role", controllers.UpdateUserRole)
+
+				// Audit log
+				admin.GET("/audit", middleware.RequirePermission(models.PermAuditView), controllers.GetAuditEvents)
+
+				// Prompt moderation
+				moderation := admin.Group("", middleware.RequirePermission(models.PermPromptsModerate))
+				moderation.GET("/moderation/rejection-reasons", controllers.GetRejectionReasons)
+				moderation.POST("/moderation/bulk", controllers.BulkModerate)
+				moderation.GET("/moderation/queue", controllers.GetModerationQueue)
+				moderation.GET("/moderation/queue/stats", controllers.GetModerationQueueStats)
+				moderation.POST("/moderation/claims", controllers.ClaimQueueItem)
+				moderation.POST("/moderation/claims/next", controllers.ClaimNextQueueItems)
+				moderation.DELETE("/moderation/claims/:kind/:id", controllers.ReleaseQueueClaim)
+				moderation.GET("/reports", controllers.GetReports)
+				moderation.PUT("/reports/:id/resolve", controllers.ResolveReport)
+
+				moderation.GET("/images", controllers.Images.AdminList)
+				moderation.GET("/images/:id", controllers.Images.AdminGet)
+				moderation.PUT("/images/:id/approve", controllers.Images.Approve)
+				moderation.PUT("/images/:id/reject", controllers.Images.Reject)
+				moderation.PUT("/images/:id/publish", controllers.Images.Publish)
+				moderation.PUT("/images/:id/unpublish", controllers.Images.Unpublish)
+				moderation.GET("/images/:id/notes", controllers.Images.GetNotes)
+				moderation.GET("/images/:id/history", controllers.Images.GetHistory)
+				moderation.POST("/images/:id/notes", controllers.Images.AddNote)
+
+				moderation.GET("/gifs", controllers.GIFs.AdminList)
+				moderation.GET("/gifs/:id", controllers.GIFs.AdminGet)
+				moderation.PUT("/gifs/:id/approve", controllers.GIFs.Approve)
+				moderation.PUT("/gifs/:id/reject", controllers.GIFs.Reject)
+				moderation.PUT("/gifs/:id/publish", controllers.GIFs.Publish)
+				moderation.PUT("/gifs/:id/unpublish", controllers.GIFs.Unpublish)
+				moderation.GET("/gifs/:id/notes", controllers.GIFs.GetNotes)
+				moderation.GET("/gifs/:id/history", controllers.GIFs.GetHistory)
+				moderation.POST("/gifs/:id/notes", controllers.GIFs.AddNote)
+
+				moderation.GET("/videos", controllers.Videos.AdminList)
+				moderation.GET("/videos/:id", controllers.Videos.AdminGet)
+				moderation.PUT("/videos/:id/approve", controllers.Videos.Approve)
+				moderation.PUT("/videos/:id/reject", controllers.Videos.Reject)
+				moderation.PUT("/videos/:id/publish", controllers.Videos.Publish)
+				moderation.PUT("/videos/:id/unpublish", controllers.Videos.Unpublish)
+				moderation.GET("/videos/:id/notes", controllers.Videos.GetNotes)
+				moderation.GET("/videos/:id/history", controllers.Videos.GetHistory)
+				moderation.POST("/videos/:id/notes", controllers.Videos.AddNote)
+
+				// Prompt management
+				promptAdmin := admin.Group("", middleware.RequirePermission(models.PermPromptsManage))
+				promptAdmin.PUT("/images/:id", controllers.Images.Update)
+				promptAdmin.PUT("/gifs/:id", controllers.GIFs.Update)
+				promptAdmin.PUT("/videos/:id", controllers.Videos.Update)
 			}
 		}
 	}
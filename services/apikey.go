@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyPrefix marks API keys so they are recognisable in configs and leaks
+	apiKeyPrefix = "aiw_"
+	// maxAPIKeysPerUser caps how many active keys a user may hold
+	maxAPIKeysPerUser = 20
+)
+
+// API key errors
+var (
+	ErrInvalidAPIKey  = errors.New("invalid or revoked API key")
+	ErrTooManyAPIKeys = errors.New("API key limit reached")
+)
+
+// withScopeList fills the JSON scope list of keys
+func withScopeList(keys []models.APIKey) []models.APIKey {
+	for i := range keys {
+		keys[i].ScopeList = strings.Split(keys[i].Scopes, ",")
+	}
+	return keys
+}
+
+// CreateAPIKey issues a new API key for a user and returns the plain key once
+func CreateAPIKey(userID uint, name string, scopes []string) (string, *models.APIKey, error) {
+	var count int64
+	if err := config.DB.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).Count(&count).Error; err != nil {
+		return "", nil, err
+	}
+	if count >= maxAPIKeysPerUser {
+		return "", nil, ErrTooManyAPIKeys
+	}
+
+	token, _, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+	raw := apiKeyPrefix + token
+
+	// Normalise scopes: unique and sorted
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	sort.Strings(unique)
+
+	key := models.APIKey{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  raw[:len(apiKeyPrefix)+6],
+		KeyHash: utils.HashToken(raw),
+		Scopes:  strings.Join(unique, ","),
+	}
+	if err := config.DB.Create(&key).Error; err != nil {
+		return "", nil, err
+	}
+
+	key.ScopeList = unique
+	return raw, &key, nil
+}
+
+// ListAPIKeys returns a user's active API keys, newest first
+func ListAPIKeys(userID uint) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	if err := config.DB.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return withScopeList(keys), nil
+}
+
+// RevokeAPIKey revokes one of a user's API keys
+func RevokeAPIKey(userID, keyID uint) error {
+	result := config.DB.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAllAPIKeys revokes every active API key of a user inside tx, e.g. one being deleted
+func RevokeAllAPIKeys(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// AuthenticateAPIKey resolves a plain API key to its key record and active owner,
+// recording when the key was last used
+func AuthenticateAPIKey(raw string) (*models.APIKey, *models.User, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	var key models.APIKey
+	if err := config.DB.Where("key_hash = ? AND revoked_at IS NULL", utils.HashToken(raw)).First(&key).Error; err != nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, key.UserID).Error; err != nil || !user.IsActive {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastSeenResolution {
+		config.DB.Model(&key).Update("last_used_at", now)
+	}
+
+	return &key, &user, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"encoding/json"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Audited actions
+const (
+	AuditPromptApprove   = "prompt.approve"
+	AuditPromptReject    = "prompt.reject"
+	AuditPromptPublish   = "prompt.publish"
+	AuditPromptUnpublish = "prompt.unpublish"
+	AuditPromptUpdate    = "prompt.update"
+	AuditPromptFeature   = "prompt.feature"
+	AuditPromptUnfeature = "prompt.unfeature"
+	AuditPromptDelete    = "prompt.delete"
+	AuditUserStatus      = "user.status"
+	AuditUserRole        = "user.role"
+	AuditUserDelete      = "user.delete"
+	AuditUserSignOut     = "user.sessions_revoke"
+	AuditTagCreate       = "tag.create"
+	AuditTagUpdate       = "tag.update"
+	AuditTagDelete       = "tag.delete"
+	AuditReportResolve   = "report.resolve"
+	AuditCommentDelete   = "comment.delete"
+)
+
+// Audit target types besides the media kind names
+const (
+	AuditTargetUser    = "user"
+	AuditTargetTag     = "tag"
+	AuditTargetReport  = "report"
+	AuditTargetComment = "comment"
+)
+
+// Actor identifies who performs an action and from where
+type Actor struct {
+	UserID    uint
+	Username  string
+	Role      string
+	IPAddress string
+	RequestID string
+}
+
+// AuditFilter holds the filters accepted by ListAuditEvents
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditSortKeys are the orderings accepted by the audit endpoint
+var AuditSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+}
+
+// RecordAudit appends an audit event inside tx. before and after are snapshots of
+// the target (nil for creations and deletions); only the fields that differ are kept.
+func RecordAudit(tx *gorm.DB, actor Actor, action, targetType string, targetID uint, before, after interface{}) error {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return err
+	}
+
+	// Keep only changed fields when both snapshots exist
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if string(value) == string(afterFields[key]) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+	delete(beforeFields, "updated_at")
+	delete(afterFields, "updated_at")
+
+	event := models.AuditEvent{
+		ActorID:       actor.UserID,
+		ActorUsername: actor.Username,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		IPAddress:     actor.IPAddress,
+		RequestID:     actor.RequestID,
+	}
+	if event.Before, err = marshalAuditFields(beforeFields); err != nil {
+		return err
+	}
+	if event.After, err = marshalAuditFields(afterFields); err != nil {
+		return err
+	}
+
+	return tx.Create(&event).Error
+}
+
+// auditFields flattens a snapshot into its JSON fields; nested objects and lists
+// (relations) are dropped
+func auditFields(snapshot interface{}) (map[string]json.RawMessage, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		if len(value) > 0 && (value[0] == '{' || value[0] == '[') {
+			delete(fields, key)
+		}
+	}
+	return fields, nil
+}
+
+func marshalAuditFields(fields map[string]json.RawMessage) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
+
+// ListAuditEvents returns one page of audit events matching the filter
+func ListAuditEvents(filter AuditFilter, page utils.PageRequest) ([]models.AuditEvent, *utils.Pagination, error) {
+	query := config.DB.Model(&models.AuditEvent{})
+
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	events := []models.AuditEvent{}
+	pagination, err := utils.Paginate(query, page, &events)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, pagination, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"ai-of-the-world-backend/models"
+)
+
+// Bulk moderation actions
+const (
+	BulkApprove   = "approve"
+	BulkReject    = "reject"
+	BulkPublish   = "publish"
+	BulkUnpublish = "unpublish"
+	BulkFeature   = "feature"
+	BulkUnfeature = "unfeature"
+	BulkDelete    = "delete"
+)
+
+// ErrUnknownAction is returned for an unsupported bulk action
+var ErrUnknownAction = errors.New("unknown bulk action")
+
+// BulkOperation is one action applied to every item of a batch
+type BulkOperation struct {
+	Action   string
+	Decision Decision // Used by reject
+}
+
+// MediaKind returns the media kind the service manages
+func (s *PromptService[T, PT]) MediaKind() MediaKind {
+	return s.Kind
+}
+
+// Table returns the database table of the media kind
+func (s *PromptService[T, PT]) Table() string {
+	return PT(new(T)).TableName()
+}
+
+// TagJoin returns the table linking the kind's prompts to tags and its prompt ID column
+func (s *PromptService[T, PT]) TagJoin() (table, column string) {
+	singular := strings.TrimSuffix(s.Table(), "s")
+	return singular + "_tags", singular + "_id"
+}
+
+// FindBase loads the shared columns of a prompt
+func (s *PromptService[T, PT]) FindBase(id uint) (*models.PromptBase, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	return prompt.Base(), nil
+}
+
+// Apply runs a bulk operation against one prompt in its own transaction
+func (s *PromptService[T, PT]) Apply(actor Actor, id uint, op BulkOperation) error {
+	var err error
+	switch op.Action {
+	case BulkApprove:
+		_, err = s.Approve(actor, id)
+	case BulkReject:
+		_, err = s.Reject(actor, id, op.Decision)
+	case BulkPublish:
+		_, err = s.Publish(actor, id)
+	case BulkUnpublish:
+		_, err = s.Unpublish(actor, id)
+	case BulkFeature:
+		_, err = s.SetFeatured(actor, id, true)
+	case BulkUnfeature:
+		_, err = s.SetFeatured(actor, id, false)
+	case BulkDelete:
+		err = s.Delete(actor, id)
+	default:
+		err = ErrUnknownAction
+	}
+	return err
+}
+
+// bulkErrors are the failures reported to callers verbatim
+var bulkErrors = []error{
+	ErrNotFound, ErrForbidden, ErrInvalidTransition, ErrNotApproved, ErrInvalidReason, ErrUnknownAction, ErrClaimed,
+}
+
+// BulkModerate applies an operation to each item independently, so one failure
+// doesn't abort the batch, and reports the outcome per item
+func BulkModerate(actor Actor, op BulkOperation, items []models.BulkItem) (*models.BulkModerationResponse, error) {
+	if op.Action == BulkReject && !IsRejectionReason(op.Decision.Reason) {
+		return nil, ErrInvalidReason
+	}
+
+	report := &models.BulkModerationResponse{
+		Action:  op.Action,
+		Results: make([]models.BulkItemResult, 0, len(items)),
+	}
+	for _, item := range items {
+		result := models.BulkItemResult{Kind: item.Kind, ID: item.ID, Success: true}
+
+		kind, ok := LookupKind(item.Kind)
+		if !ok {
+			result.Success, result.Error = false, ErrInvalidMediaType.Error()
+		} else if err := kind.Apply(actor, item.ID, op); err != nil {
+			result.Success, result.Error = false, bulkErrorMessage(err)
+			if result.Error == "" {
+				log.Printf("⚠️  Bulk %s of %s %d failed: %v", op.Action, item.Kind, item.ID, err)
+				result.Error = "internal error"
+			}
+		}
+
+		if result.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// bulkErrorMessage returns the client-facing message of a known error, or "" otherwise
+func bulkErrorMessage(err error) string {
+	for _, known := range bulkErrors {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,544 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Collection errors
+var (
+	ErrAlreadyCollected = errors.New("prompt is already in this collection")
+	ErrAlreadyEditor    = errors.New("user is already an editor of this collection")
+	ErrInviteSelf       = errors.New("you already own this collection")
+)
+
+// CollectionSortKeys are the orderings accepted by collection listings
+var CollectionSortKeys = []utils.SortKey{
+	{Name: "updated_at", Expr: "updated_at", Time: true},
+	{Name: "created_at", Expr: "created_at", Time: true},
+	{Name: "items_count", Expr: "items_count"},
+	{Name: "name", Expr: "name", Asc: true},
+}
+
+// CollectionItemSortKeys are the orderings accepted by the collection items listing
+var CollectionItemSortKeys = []utils.SortKey{
+	{Name: "position", Expr: "position", Asc: true},
+	{Name: "created_at", Expr: "created_at", Time: true},
+}
+
+// editorsOf selects the IDs of collections the user has accepted to edit
+func editorsOf(userID uint) *gorm.DB {
+	return config.DB.Model(&models.CollectionEditor{}).Select("collection_id").
+		Where("user_id = ? AND status = ?", userID, models.EditorAccepted)
+}
+
+// listedCollections restricts a collection query to the ones the viewer may see
+// listed: public ones, plus any they own or edit
+func listedCollections(query *gorm.DB, viewer Viewer) *gorm.DB {
+	if viewer.UserID == 0 {
+		return query.Where("collections.visibility = ?", models.CollectionPublic)
+	}
+	return query.Where("(collections.visibility = ? OR collections.user_id = ? OR collections.id IN (?))",
+		models.CollectionPublic, viewer.UserID, editorsOf(viewer.UserID))
+}
+
+// isCollectionEditor reports whether the user owns or has accepted to edit a collection
+func isCollectionEditor(collection *models.Collection, userID uint) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	if collection.UserID == userID {
+		return true, nil
+	}
+	var count int64
+	err := config.DB.Model(&models.CollectionEditor{}).
+		Where("collection_id = ? AND user_id = ? AND status = ?", collection.ID, userID, models.EditorAccepted).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// findCollection loads a collection without relations
+func findCollection(id uint) (*models.Collection, error) {
+	var collection models.Collection
+	if err := config.DB.First(&collection, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// viewableCollection loads a collection the viewer may open. Private collections
+// of others are reported as not found.
+func viewableCollection(viewer Viewer, id uint) (*models.Collection, error) {
+	collection, err := findCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	if collection.Visibility != models.CollectionPrivate {
+		return collection, nil
+	}
+	editor, err := isCollectionEditor(collection, viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !editor {
+		return nil, ErrNotFound
+	}
+	return collection, nil
+}
+
+// editableCollection loads a collection the actor may curate, as owner or editor
+func editableCollection(actor Actor, id uint) (*models.Collection, error) {
+	collection, err := viewableCollection(Viewer{UserID: actor.UserID}, id)
+	if err != nil {
+		return nil, err
+	}
+	editor, err := isCollectionEditor(collection, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !editor {
+		return nil, ErrForbidden
+	}
+	return collection, nil
+}
+
+// ownedCollection loads a collection owned by the actor
+func ownedCollection(actor Actor, id uint) (*models.Collection, error) {
+	collection, err := viewableCollection(Viewer{UserID: actor.UserID}, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection.UserID != actor.UserID {
+		return nil, ErrForbidden
+	}
+	return collection, nil
+}
+
+// withOwners sets the public view of each loaded collection's owner
+func withOwners(collections []models.Collection) {
+	for i := range collections {
+		owner := summarize(collections[i].User)
+		collections[i].Owner = &owner
+	}
+}
+
+// reloadCollection loads a collection with its owner and editors. Pending
+// invitations are only shown to the owner.
+func reloadCollection(id uint, viewerID uint) (*models.Collection, error) {
+	var collection models.Collection
+	if err := config.DB.Preload("User").First(&collection, id).Error; err != nil {
+		return nil, err
+	}
+	owner := summarize(collection.User)
+	collection.Owner = &owner
+
+	editors := config.DB.Preload("User").Where("collection_id = ?", id)
+	if collection.UserID != viewerID {
+		editors = editors.Where("status = ?", models.EditorAccepted)
+	}
+	if err := editors.Find(&collection.Editors).Error; err != nil {
+		return nil, err
+	}
+	for i := range collection.Editors {
+		editor := summarize(collection.Editors[i].User)
+		collection.Editors[i].Editor = &editor
+	}
+	return &collection, nil
+}
+
+// CreateCollection creates an empty collection owned by the actor
+func CreateCollection(actor Actor, req models.CollectionRequest) (*models.Collection, error) {
+	collection := models.Collection{
+		UserID:      actor.UserID,
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  req.Visibility,
+	}
+	if collection.Visibility == "" {
+		collection.Visibility = models.CollectionPrivate
+	}
+	if err := config.DB.Create(&collection).Error; err != nil {
+		return nil, err
+	}
+	return reloadCollection(collection.ID, actor.UserID)
+}
+
+// GetCollection returns a collection the viewer may open
+func GetCollection(viewer Viewer, id uint) (*models.Collection, error) {
+	if _, err := viewableCollection(viewer, id); err != nil {
+		return nil, err
+	}
+	return reloadCollection(id, viewer.UserID)
+}
+
+// UpdateCollection changes a collection's name, description and visibility (Owner only)
+func UpdateCollection(actor Actor, id uint, req models.CollectionRequest) (*models.Collection, error) {
+	collection, err := ownedCollection(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	collection.Name = req.Name
+	collection.Description = req.Description
+	if req.Visibility != "" {
+		collection.Visibility = req.Visibility
+	}
+	if err := config.DB.Model(collection).Select("Name", "Description", "Visibility").Updates(collection).Error; err != nil {
+		return nil, err
+	}
+	return reloadCollection(id, actor.UserID)
+}
+
+// DeleteCollection removes a collection with its items and editors (Owner only)
+func DeleteCollection(actor Actor, id uint) error {
+	collection, err := ownedCollection(actor, id)
+	if err != nil {
+		return err
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", id).Delete(&models.CollectionEditor{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(collection).Error
+	})
+}
+
+// ListCollectionItems returns one page of a collection's prompts in order. Prompts
+// hidden from the viewer are left out.
+func ListCollectionItems(viewer Viewer, id uint, page utils.PageRequest) ([]models.CollectionEntry, *utils.Pagination, error) {
+	if _, err := viewableCollection(viewer, id); err != nil {
+		return nil, nil, err
+	}
+
+	items := []models.CollectionItem{}
+	pagination, err := utils.Paginate(config.DB.Model(&models.CollectionItem{}).Where("collection_id = ?", id), page, &items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]PromptRef, len(items))
+	for i, item := range items {
+		refs[i] = PromptRef{Kind: item.TargetType, ID: item.TargetID}
+	}
+	prompts, err := LoadPrompts(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]models.CollectionEntry, 0, len(items))
+	for i, item := range items {
+		if prompts[i] == nil {
+			continue
+		}
+		entries = append(entries, models.CollectionEntry{
+			ItemID:   item.ID,
+			Position: item.Position,
+			Kind:     item.TargetType,
+			AddedBy:  item.AddedBy,
+			AddedAt:  item.CreatedAt,
+			Prompt:   prompts[i],
+		})
+	}
+	return entries, pagination, nil
+}
+
+// lockCollection locks a collection row for the rest of the transaction so item
+// positions stay dense under concurrent edits
+func lockCollection(tx *gorm.DB, id uint) (*models.Collection, error) {
+	var collection models.Collection
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&collection, id).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// AddCollectionItem adds a prompt the actor can see to a collection they curate, at
+// the given position or at the end
+func AddCollectionItem(actor Actor, id uint, req models.AddCollectionItemRequest) (*models.CollectionItem, error) {
+	if _, err := editableCollection(actor, id); err != nil {
+		return nil, err
+	}
+	if _, _, err := visiblePrompt(Viewer{UserID: actor.UserID}, req.Kind, req.ID); err != nil {
+		return nil, err
+	}
+
+	item := models.CollectionItem{CollectionID: id, TargetType: req.Kind, TargetID: req.ID, AddedBy: actor.UserID}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockCollection(tx, id)
+		if err != nil {
+			return err
+		}
+
+		var exists int64
+		if err := tx.Model(&models.CollectionItem{}).
+			Where("collection_id = ? AND target_type = ? AND target_id = ?", id, req.Kind, req.ID).
+			Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists > 0 {
+			return ErrAlreadyCollected
+		}
+
+		item.Position = req.Position
+		if item.Position == 0 || item.Position > collection.ItemsCount {
+			item.Position = collection.ItemsCount + 1
+		} else if err := tx.Model(&models.CollectionItem{}).
+			Where("collection_id = ? AND position >= ?", id, item.Position).
+			UpdateColumn("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		return tx.Model(collection).Updates(map[string]interface{}{"items_count": gorm.Expr("items_count + 1")}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// findCollectionItem loads an item of a collection
+func findCollectionItem(tx *gorm.DB, collectionID, itemID uint) (*models.CollectionItem, error) {
+	var item models.CollectionItem
+	if err := tx.Where("collection_id = ?", collectionID).First(&item, itemID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// removeItem deletes an item and closes the gap it leaves
+func removeItem(tx *gorm.DB, item *models.CollectionItem) error {
+	if err := tx.Delete(item).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&models.CollectionItem{}).
+		Where("collection_id = ? AND position > ?", item.CollectionID, item.Position).
+		UpdateColumn("position", gorm.Expr("position - 1")).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Collection{}).Where("id = ?", item.CollectionID).
+		Updates(map[string]interface{}{"items_count": gorm.Expr("GREATEST(items_count - 1, 0)")}).Error
+}
+
+// RemoveCollectionItem removes an item from a collection the actor curates
+func RemoveCollectionItem(actor Actor, id, itemID uint) error {
+	if _, err := editableCollection(actor, id); err != nil {
+		return err
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockCollection(tx, id); err != nil {
+			return err
+		}
+		item, err := findCollectionItem(tx, id, itemID)
+		if err != nil {
+			return err
+		}
+		return removeItem(tx, item)
+	})
+}
+
+// MoveCollectionItem moves an item to a new position, shifting the items between
+func MoveCollectionItem(actor Actor, id, itemID uint, position int) (*models.CollectionItem, error) {
+	if _, err := editableCollection(actor, id); err != nil {
+		return nil, err
+	}
+
+	var item *models.CollectionItem
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		collection, err := lockCollection(tx, id)
+		if err != nil {
+			return err
+		}
+		if item, err = findCollectionItem(tx, id, itemID); err != nil {
+			return err
+		}
+
+		if position > collection.ItemsCount {
+			position = collection.ItemsCount
+		}
+		from := item.Position
+		switch {
+		case position < from:
+			err = tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND position >= ? AND position < ?", id, position, from).
+				UpdateColumn("position", gorm.Expr("position + 1")).Error
+		case position > from:
+			err = tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND position > ? AND position <= ?", id, from, position).
+				UpdateColumn("position", gorm.Expr("position - 1")).Error
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		item.Position = position
+		if err := tx.Model(item).UpdateColumn("position", position).Error; err != nil {
+			return err
+		}
+		return tx.Model(collection).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// removePromptFromCollections takes a prompt that is being deleted out of every collection
+func removePromptFromCollections(tx *gorm.DB, kindName string, id uint) error {
+	var items []models.CollectionItem
+	if err := tx.Where("target_type = ? AND target_id = ?", kindName, id).Find(&items).Error; err != nil {
+		return err
+	}
+	for i := range items {
+		if err := removeItem(tx, &items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListUserCollections returns one page of the collections a user owns that the
+// viewer may see listed. With shared set, collections the user edits are included.
+func ListUserCollections(viewer Viewer, userID uint, shared bool, page utils.PageRequest) ([]models.Collection, *utils.Pagination, error) {
+	query := config.DB.Model(&models.Collection{})
+	if shared {
+		query = query.Where("(user_id = ? OR id IN (?))", userID, editorsOf(userID))
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	collections := []models.Collection{}
+	pagination, err := utils.Paginate(listedCollections(query, viewer), page, &collections, "User")
+	if err != nil {
+		return nil, nil, err
+	}
+	withOwners(collections)
+	return collections, pagination, nil
+}
+
+// ListPromptCollections returns one page of the collections a visible prompt appears
+// in that the viewer may see listed
+func ListPromptCollections(viewer Viewer, kindName string, id uint, page utils.PageRequest) ([]models.Collection, *utils.Pagination, error) {
+	if _, _, err := visiblePrompt(viewer, kindName, id); err != nil {
+		return nil, nil, err
+	}
+
+	containing := config.DB.Model(&models.CollectionItem{}).Select("collection_id").
+		Where("target_type = ? AND target_id = ?", kindName, id)
+	query := config.DB.Model(&models.Collection{}).Where("id IN (?)", containing)
+
+	collections := []models.Collection{}
+	pagination, err := utils.Paginate(listedCollections(query, viewer), page, &collections, "User")
+	if err != nil {
+		return nil, nil, err
+	}
+	withOwners(collections)
+	return collections, pagination, nil
+}
+
+// InviteEditor invites an active user to curate a collection (Owner only)
+func InviteEditor(actor Actor, id uint, username string) (*models.CollectionEditor, error) {
+	collection, err := ownedCollection(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := config.DB.Where("username = ? AND is_active = ?", username, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if user.ID == collection.UserID {
+		return nil, ErrInviteSelf
+	}
+
+	editor := models.CollectionEditor{
+		CollectionID: id,
+		UserID:       user.ID,
+		Status:       models.EditorInvited,
+		InvitedBy:    actor.UserID,
+	}
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Omit("User").Create(&editor)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAlreadyEditor
+	}
+	summary := summarize(user)
+	editor.Editor = &summary
+	return &editor, nil
+}
+
+// AcceptInvite accepts the actor's pending invitation to edit a collection
+func AcceptInvite(actor Actor, id uint) (*models.Collection, error) {
+	now := time.Now()
+	result := config.DB.Model(&models.CollectionEditor{}).
+		Where("collection_id = ? AND user_id = ? AND status = ?", id, actor.UserID, models.EditorInvited).
+		Updates(map[string]interface{}{"status": models.EditorAccepted, "accepted_at": now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return reloadCollection(id, actor.UserID)
+}
+
+// RemoveEditor removes an editor or invitation. Owners may remove anyone; editors
+// and invitees may remove themselves to leave or decline.
+func RemoveEditor(actor Actor, id, userID uint) error {
+	collection, err := findCollection(id)
+	if err != nil {
+		return err
+	}
+	if actor.UserID != collection.UserID && actor.UserID != userID {
+		return ErrForbidden
+	}
+
+	result := config.DB.Where("collection_id = ? AND user_id = ?", id, userID).Delete(&models.CollectionEditor{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListInvites returns the collections the actor has been invited to edit
+func ListInvites(actor Actor) ([]models.Collection, error) {
+	invited := config.DB.Model(&models.CollectionEditor{}).Select("collection_id").
+		Where("user_id = ? AND status = ?", actor.UserID, models.EditorInvited)
+
+	collections := []models.Collection{}
+	if err := config.DB.Preload("User").Where("id IN (?)", invited).Order("updated_at DESC").Find(&collections).Error; err != nil {
+		return nil, err
+	}
+	withOwners(collections)
+	return collections, nil
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Comment errors
+var (
+	ErrInvalidParent  = errors.New("parent comment is not on this prompt")
+	ErrCommentDeleted = errors.New("comment is deleted")
+)
+
+// maxMentions caps how many users one comment can mention
+const maxMentions = 20
+
+// mentionPattern matches @username mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]{3,100})`)
+
+// CommentSortKeys are the orderings accepted by comment listings
+var CommentSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+	{Name: "replies_count", Expr: "replies_count"},
+}
+
+// ParseMentions returns the distinct usernames mentioned in a comment body, in order
+func ParseMentions(body string) []string {
+	var usernames []string
+	seen := map[string]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// Trailing punctuation ends a sentence rather than the username
+		username := strings.TrimRight(match[1], ".-")
+		key := strings.ToLower(username)
+		if len(username) < 3 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+	return usernames
+}
+
+// setMentions replaces a comment's mentions with the existing users its body names
+func setMentions(tx *gorm.DB, comment *models.Comment) error {
+	if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentMention{}).Error; err != nil {
+		return err
+	}
+	comment.Mentions = nil
+
+	usernames := ParseMentions(comment.Body)
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	var users []models.User
+	if err := tx.Select("id, username").Where("username IN ?", usernames).Find(&users).Error; err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+
+	mentions := make([]models.CommentMention, len(users))
+	for i, user := range users {
+		mentions[i] = models.CommentMention{CommentID: comment.ID, UserID: user.ID, Username: user.Username}
+	}
+	if err := tx.Create(&mentions).Error; err != nil {
+		return err
+	}
+	comment.Mentions = mentions
+	return nil
+}
+
+// visiblePrompt loads a prompt the viewer may see
+func visiblePrompt(viewer Viewer, kindName string, id uint) (KindService, *models.PromptBase, error) {
+	kind, ok := LookupKind(kindName)
+	if !ok {
+		return nil, nil, ErrInvalidMediaType
+	}
+	base, err := kind.FindBase(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !viewer.CanView(base) {
+		return nil, nil, ErrNotFound
+	}
+	return kind, base, nil
+}
+
+// adjustComments moves a prompt's comment count by delta
+func adjustComments(tx *gorm.DB, kind KindService, id uint, delta int) error {
+	return tx.Table(kind.Table()).Where("id = ?", id).
+		UpdateColumn("comments_count", gorm.Expr("GREATEST(comments_count + ?, 0)", delta)).Error
+}
+
+// findComment loads a comment without relations
+func findComment(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := config.DB.First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// reloadComment loads a comment with its author and mentions
+func reloadComment(id uint) (*models.Comment, error) {
+	comments := make([]models.Comment, 1)
+	if err := config.DB.Preload("User").Preload("Mentions").First(&comments[0], id).Error; err != nil {
+		return nil, err
+	}
+	presentComments(comments)
+	return &comments[0], nil
+}
+
+// presentComments sets the public view of each comment's author and blanks deleted
+// comments kept as thread placeholders
+func presentComments(comments []models.Comment) {
+	for i := range comments {
+		if comments[i].DeletedAt != nil {
+			comments[i].Body = ""
+			comments[i].Mentions = nil
+			comments[i].Author = nil
+			continue
+		}
+		author := summarize(comments[i].User)
+		comments[i].Author = &author
+	}
+}
+
+// CreateComment adds a comment, or a reply when parentID is set, to a prompt the
+// author can see
+func CreateComment(author Actor, kindName string, id uint, body string, parentID *uint) (*models.Comment, error) {
+	kind, _, err := visiblePrompt(Viewer{UserID: author.UserID}, kindName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		parent, err := findComment(*parentID)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, ErrInvalidParent
+			}
+			return nil, err
+		}
+		if parent.TargetType != kindName || parent.TargetID != id {
+			return nil, ErrInvalidParent
+		}
+		if parent.DeletedAt != nil {
+			return nil, ErrCommentDeleted
+		}
+	}
+
+	comment := models.Comment{
+		TargetType: kindName,
+		TargetID:   id,
+		ParentID:   parentID,
+		UserID:     author.UserID,
+		Body:       strings.TrimSpace(body),
+	}
+	if comment.Body == "" {
+		return nil, ErrMissingFields
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User", "Mentions").Create(&comment).Error; err != nil {
+			return err
+		}
+		if err := setMentions(tx, &comment); err != nil {
+			return err
+		}
+		if parentID != nil {
+			if err := tx.Model(&models.Comment{}).Where("id = ?", *parentID).
+				UpdateColumn("replies_count", gorm.Expr("replies_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+		return adjustComments(tx, kind, id, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reloadComment(comment.ID)
+}
+
+// ListComments returns one page of a prompt's top-level comments. Deleted comments
+// are kept, without their text, only while they have replies.
+func ListComments(viewer Viewer, kindName string, id uint, page utils.PageRequest) ([]models.Comment, *utils.Pagination, error) {
+	if _, _, err := visiblePrompt(viewer, kindName, id); err != nil {
+		return nil, nil, err
+	}
+
+	query := config.DB.Model(&models.Comment{}).
+		Where("target_type = ? AND target_id = ? AND parent_id IS NULL", kindName, id).
+		Where("deleted_at IS NULL OR replies_count > 0")
+	return listComments(query, page)
+}
+
+// ListReplies returns one page of the direct replies to a comment on a prompt the
+// viewer can see
+func ListReplies(viewer Viewer, commentID uint, page utils.PageRequest) ([]models.Comment, *utils.Pagination, error) {
+	parent, err := findComment(commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, _, err := visiblePrompt(viewer, parent.TargetType, parent.TargetID); err != nil {
+		return nil, nil, ErrNotFound
+	}
+
+	query := config.DB.Model(&models.Comment{}).
+		Where("parent_id = ?", commentID).
+		Where("deleted_at IS NULL OR replies_count > 0")
+	return listComments(query, page)
+}
+
+func listComments(query *gorm.DB, page utils.PageRequest) ([]models.Comment, *utils.Pagination, error) {
+	comments := []models.Comment{}
+	pagination, err := utils.Paginate(query, page, &comments, "User", "Mentions")
+	if err != nil {
+		return nil, nil, err
+	}
+	presentComments(comments)
+	return comments, pagination, nil
+}
+
+// UpdateComment edits the text of the author's own comment
+func UpdateComment(actor Actor, commentID uint, body string) (*models.Comment, error) {
+	comment, err := findComment(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != actor.UserID {
+		return nil, ErrForbidden
+	}
+	if comment.DeletedAt != nil {
+		return nil, ErrCommentDeleted
+	}
+
+	comment.Body = strings.TrimSpace(body)
+	if comment.Body == "" {
+		return nil, ErrMissingFields
+	}
+	now := time.Now()
+	comment.EditedAt = &now
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Select("Body", "EditedAt").Updates(comment).Error; err != nil {
+			return err
+		}
+		return setMentions(tx, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reloadComment(comment.ID)
+}
+
+// DeleteComment soft deletes a comment. Its author and the prompt's owner may delete
+// it, as may staff with comments.moderate, whose deletions are audited.
+func DeleteComment(actor Actor, commentID uint) error {
+	comment, err := findComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.DeletedAt != nil {
+		return ErrNotFound
+	}
+
+	kind, ok := LookupKind(comment.TargetType)
+	if !ok {
+		return ErrNotFound
+	}
+	base, err := kind.FindBase(comment.TargetID)
+	if err != nil {
+		return err
+	}
+
+	isAuthor := comment.UserID == actor.UserID
+	isPromptOwner := base.UserID == actor.UserID
+	if !isAuthor && !isPromptOwner && !models.HasPermission(actor.Role, models.PermCommentsModerate) {
+		return ErrForbidden
+	}
+
+	before := *comment
+	now := time.Now()
+	comment.DeletedAt = &now
+	comment.DeletedBy = &actor.UserID
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Select("DeletedAt", "DeletedBy").Updates(comment).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentMention{}).Error; err != nil {
+			return err
+		}
+		if err := adjustComments(tx, kind, comment.TargetID, -1); err != nil {
+			return err
+		}
+		if isAuthor || isPromptOwner {
+			return nil
+		}
+		return RecordAudit(tx, actor, AuditCommentDelete, AuditTargetComment, comment.ID, before, comment)
+	})
+}
+
+// deletePromptComments removes every comment on a prompt that is being deleted
+func deletePromptComments(tx *gorm.DB, kindName string, id uint) error {
+	comments := tx.Model(&models.Comment{}).Select("id").Where("target_type = ? AND target_id = ?", kindName, id)
+	if err := tx.Where("comment_id IN (?)", comments).Delete(&models.CommentMention{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("target_type = ? AND target_id = ?", kindName, id).Delete(&models.Comment{}).Error
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Engagement counter columns shared by every prompt table
+const (
+	CounterViews     = "views_count"
+	CounterDownloads = "downloads_count"
+)
+
+// DedupStore remembers which viewers recently hit a prompt. Implementations must be
+// safe for concurrent use; a shared store (e.g. Redis) dedupes across instances.
+type DedupStore interface {
+	// FirstSeen records key and reports whether it was not already seen within window
+	FirstSeen(key string, window time.Duration, now time.Time) bool
+}
+
+// ViewDedup is the store used to dedupe views and downloads; replace it at startup to share it
+var ViewDedup DedupStore = NewMemoryDedupStore()
+
+// MemoryDedupStore keeps recent keys in process memory; suitable for a single instance
+type MemoryDedupStore struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryDedupStore creates an empty in-memory store
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{seen: make(map[string]time.Time)}
+}
+
+func (s *MemoryDedupStore) FirstSeen(key string, window time.Duration, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired keys at most once per window
+	if now.Sub(s.lastSweep) >= window {
+		s.lastSweep = now
+		for k, at := range s.seen {
+			if now.Sub(at) >= window {
+				delete(s.seen, k)
+			}
+		}
+	}
+
+	if at, ok := s.seen[key]; ok && now.Sub(at) < window {
+		return false
+	}
+	s.seen[key] = now
+	return true
+}
+
+// counterKey identifies one counter column of one prompt
+type counterKey struct {
+	Kind   string
+	Column string
+	ID     uint
+}
+
+// CounterBuffer accumulates counter increments in memory so hot prompts cost one
+// UPDATE per flush instead of one per hit
+type CounterBuffer struct {
+	mu      sync.Mutex
+	pending map[counterKey]int
+}
+
+// Counters buffers view and download counts until the next flush
+var Counters = &CounterBuffer{pending: make(map[counterKey]int)}
+
+// Add buffers an increment of a prompt's counter column
+func (b *CounterBuffer) Add(kind, column string, id uint, delta int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending[counterKey{Kind: kind, Column: column, ID: id}] += delta
+}
+
+// Pending returns the buffered, not yet flushed increment of a counter
+func (b *CounterBuffer) Pending(kind, column string, id uint) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pending[counterKey{Kind: kind, Column: column, ID: id}]
+}
+
+// Flush writes buffered increments to the prompt tables, one UPDATE per kind, column
+// and delta, and adds them to today's engagement buckets. A failed flush puts the
+// increments back for the next one.
+func (b *CounterBuffer) Flush() error {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[counterKey]int)
+	b.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Group prompts that share an increment so they update together
+	type group struct {
+		Kind   string
+		Column string
+		Delta  int
+	}
+	type target struct {
+		Kind string
+		ID   uint
+	}
+	groups := map[group][]uint{}
+	days := map[target]*models.EngagementDay{}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for key, delta := range pending {
+		g := group{Kind: key.Kind, Column: key.Column, Delta: delta}
+		groups[g] = append(groups[g], key.ID)
+
+		t := target{Kind: key.Kind, ID: key.ID}
+		day, ok := days[t]
+		if !ok {
+			day = &models.EngagementDay{TargetType: key.Kind, TargetID: key.ID, Day: today}
+			days[t] = day
+		}
+		if key.Column == CounterViews {
+			day.Views += delta
+		} else {
+			day.Downloads += delta
+		}
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for g, ids := range groups {
+			kind, ok := LookupKind(g.Kind)
+			if !ok {
+				continue
+			}
+			if err := tx.Table(kind.Table()).Where("id IN ?", ids).
+				UpdateColumn(g.Column, gorm.Expr(g.Column+" + ?", g.Delta)).Error; err != nil {
+				return err
+			}
+		}
+
+		rows := make([]*models.EngagementDay, 0, len(days))
+		for _, day := range days {
+			rows = append(rows, day)
+		}
+		return tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"views":     gorm.Expr("views + VALUES(views)"),
+				"downloads": gorm.Expr("downloads + VALUES(downloads)"),
+			}),
+		}).Create(&rows).Error
+	})
+	if err != nil {
+		for key, delta := range pending {
+			b.Add(key.Kind, key.Column, key.ID, delta)
+		}
+	}
+	return err
+}
+
+// StartCounterFlusher flushes Counters every interval until stop is closed, then
+// flushes once more
+func StartCounterFlusher(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := Counters.Flush(); err != nil {
+				log.Println("⚠️  Failed to flush engagement counters:", err)
+			}
+		case <-stop:
+			if err := Counters.Flush(); err != nil {
+				log.Println("⚠️  Failed to flush engagement counters:", err)
+			}
+			return
+		}
+	}
+}
+
+// count adds one to a prompt's counter column unless the viewer already hit it within
+// the dedup window. Owners' own hits don't count.
+func (s *PromptService[T, PT]) count(viewer Viewer, column string, base *models.PromptBase) bool {
+	if viewer.UserID != 0 && viewer.UserID == base.UserID {
+		return false
+	}
+
+	key := column + ":" + s.Kind.Name + ":" + strconv.FormatUint(uint64(base.ID), 10) + ":" + viewer.key()
+	if !ViewDedup.FirstSeen(key, config.AppConfig.ViewDedupWindow, time.Now()) {
+		return false
+	}
+	Counters.Add(s.Kind.Name, column, base.ID, 1)
+	return true
+}
+
+// withPendingCounts adds buffered, unflushed hits to a loaded prompt's counters
+func (s *PromptService[T, PT]) withPendingCounts(base *models.PromptBase) {
+	base.ViewsCount += Counters.Pending(s.Kind.Name, CounterViews, base.ID)
+	base.DownloadsCount += Counters.Pending(s.Kind.Name, CounterDownloads, base.ID)
+}
+
+// RecordView counts a view of a prompt the viewer just fetched
+func (s *PromptService[T, PT]) RecordView(viewer Viewer, prompt PT) {
+	if s.count(viewer, CounterViews, prompt.Base()) {
+		prompt.Base().ViewsCount++
+	}
+}
+
+// Download counts a download of a visible prompt and returns a URL the client can
+// fetch the asset from
+func (s *PromptService[T, PT]) Download(viewer Viewer, id uint) (string, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return "", err
+	}
+	if !viewer.CanView(prompt.Base()) || prompt.MediaURL() == "" {
+		return "", ErrNotFound
+	}
+
+	url, err := s.Kind.Storage.SignURL(prompt.MediaURL())
+	if err != nil {
+		return "", err
+	}
+	s.count(viewer, CounterDownloads, prompt.Base())
+	return url, nil
+}
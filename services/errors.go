@@ -0,0 +1,15 @@
+package services
+
+import "errors"
+
+// Errors returned by the service layer; controllers map them to HTTP statuses
+var (
+	ErrNotFound          = errors.New("record not found")
+	ErrForbidden         = errors.New("permission denied")
+	ErrMissingFields     = errors.New("missing required fields")
+	ErrInvalidMediaType  = errors.New("invalid media type")
+	ErrUploadFailed      = errors.New("upload failed")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrNotApproved       = errors.New("prompt is not approved")
+	ErrInvalidReason     = errors.New("unknown rejection reason")
+)
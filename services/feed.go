@@ -0,0 +1,136 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// feedCursor marks the last item of a feed page. IDs repeat across kinds, so the
+// kind is part of the position.
+type feedCursor struct {
+	PublishedAt time.Time `json:"t"`
+	Kind        string    `json:"k"`
+	ID          uint      `json:"id"`
+}
+
+func (c feedCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeFeedCursor(encoded string) (*feedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", utils.ErrInvalidPageRequest)
+	}
+	var cur feedCursor
+	if err := json.Unmarshal(raw, &cur); err != nil || cur.Kind == "" {
+		return nil, fmt.Errorf("%w: invalid cursor", utils.ErrInvalidPageRequest)
+	}
+	return &cur, nil
+}
+
+// FeedSortKeys are the orderings accepted by feeds, which only page by cursor
+var FeedSortKeys = []utils.SortKey{
+	{Name: "published_at", Expr: "published_at", Time: true},
+}
+
+// feedRow is a published prompt of any kind in a merged feed
+type feedRow struct {
+	Kind        string
+	ID          uint
+	PublishedAt time.Time
+}
+
+// publishedFeed pages through the approved, published prompts of every kind matched
+// by scope, newest publication first (then kind and ID, descending)
+func publishedFeed(viewer Viewer, scope func(query *gorm.DB) *gorm.DB, limit int, cursor string) ([]models.FeedItem, *utils.Pagination, error) {
+	var after *feedCursor
+	if cursor != "" {
+		var err error
+		if after, err = decodeFeedCursor(cursor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pagination := &utils.Pagination{Limit: limit, Sort: "published_at", Order: "desc"}
+	parts := make([]string, 0, len(Kinds))
+	args := make([]interface{}, 0, len(Kinds))
+	for _, kind := range Kinds {
+		name := kind.MediaKind().Name
+		query := scope(config.DB.Table(kind.Table()).
+			Where("status = ? AND is_published = ? AND published_at IS NOT NULL", models.PromptStatusApproved, true))
+
+		var count int64
+		if err := query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+			return nil, nil, err
+		}
+		pagination.Total += count
+
+		// Each kind only contributes rows that sort after the cursor
+		if after != nil {
+			switch {
+			case name < after.Kind:
+				query = query.Where("published_at <= ?", after.PublishedAt)
+			case name == after.Kind:
+				query = query.Where("(published_at < ? OR (published_at = ? AND id < ?))", after.PublishedAt, after.PublishedAt, after.ID)
+			default:
+				query = query.Where("published_at < ?", after.PublishedAt)
+			}
+		}
+
+		parts = append(parts, "(?)")
+		args = append(args, query.Select("? AS kind, id, published_at", name).
+			Order("published_at DESC, id DESC").Limit(limit+1))
+	}
+
+	var rows []feedRow
+	if err := config.DB.Table("("+strings.Join(parts, " UNION ALL ")+") AS feed", args...).
+		Order("published_at DESC, kind DESC, id DESC").
+		Limit(limit + 1).
+		Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[limit-1]
+		pagination.NextCursor = feedCursor{PublishedAt: last.PublishedAt, Kind: last.Kind, ID: last.ID}.encode()
+	}
+
+	refs := make([]PromptRef, len(rows))
+	for i, row := range rows {
+		refs[i] = PromptRef{Kind: row.Kind, ID: row.ID}
+	}
+	prompts, err := LoadPrompts(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]models.FeedItem, 0, len(rows))
+	for i, row := range rows {
+		if prompts[i] == nil {
+			continue
+		}
+		items = append(items, models.FeedItem{Kind: row.Kind, PublishedAt: row.PublishedAt, Prompt: prompts[i]})
+	}
+	return items, pagination, nil
+}
+
+// FollowingFeed returns one page of the prompts newly published by the creators the
+// viewer follows, across every media kind
+func FollowingFeed(viewer Viewer, limit int, cursor string) ([]models.FeedItem, *utils.Pagination, error) {
+	followees := config.DB.Model(&models.Follow{}).Select("followee_id").Where("follower_id = ?", viewer.UserID)
+	return publishedFeed(viewer, func(query *gorm.DB) *gorm.DB {
+		return query.Where("user_id IN (?)", followees)
+	}, limit, cursor)
+}
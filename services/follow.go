@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrFollowSelf is returned when a user tries to follow themselves
+var ErrFollowSelf = errors.New("you cannot follow yourself")
+
+// FollowSortKeys are the orderings accepted by follower and following lists
+var FollowSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+}
+
+// findActiveUser loads an active user
+func findActiveUser(id uint) (*models.User, error) {
+	var user models.User
+	if err := config.DB.Where("is_active = ?", true).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// summarize returns the public view of a user shown in lists
+func summarize(user models.User) models.UserSummary {
+	return models.UserSummary{
+		ID:                user.ID,
+		Username:          user.Username,
+		FullName:          user.FullName,
+		ProfilePictureURL: user.ProfilePictureURL,
+		IsVerified:        user.IsVerified,
+	}
+}
+
+// isFollowing reports whether follower follows followee
+func isFollowing(followerID, followeeID uint) (bool, error) {
+	if followerID == 0 {
+		return false, nil
+	}
+	var count int64
+	err := config.DB.Model(&models.Follow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// followStatus reads a user's follower count and whether the follower follows them
+func followStatus(followerID, followeeID uint) (*models.FollowStatus, error) {
+	status := &models.FollowStatus{}
+	if err := config.DB.Model(&models.User{}).Select("followers_count").Where("id = ?", followeeID).
+		Row().Scan(&status.FollowersCount); err != nil {
+		return nil, err
+	}
+	following, err := isFollowing(followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	status.Following = following
+	return status, nil
+}
+
+// adjustFollowCounts atomically moves the follower's following count and the
+// followee's follower count by delta
+func adjustFollowCounts(tx *gorm.DB, followerID, followeeID uint, delta int) error {
+	if err := tx.Model(&models.User{}).Where("id = ?", followeeID).
+		UpdateColumn("followers_count", gorm.Expr("GREATEST(followers_count + ?, 0)", delta)).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", followerID).
+		UpdateColumn("following_count", gorm.Expr("GREATEST(following_count + ?, 0)", delta)).Error
+}
+
+// Follow makes the actor follow an active user. Following twice is a no-op.
+func Follow(actor Actor, userID uint) (*models.FollowStatus, error) {
+	if userID == actor.UserID {
+		return nil, ErrFollowSelf
+	}
+	if _, err := findActiveUser(userID); err != nil {
+		return nil, err
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		follow := models.Follow{FollowerID: actor.UserID, FolloweeID: userID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return adjustFollowCounts(tx, actor.UserID, userID, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return followStatus(actor.UserID, userID)
+}
+
+// Unfollow stops the actor following a user. Unfollowing someone not followed is a no-op.
+func Unfollow(actor Actor, userID uint) (*models.FollowStatus, error) {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("follower_id = ? AND followee_id = ?", actor.UserID, userID).Delete(&models.Follow{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return adjustFollowCounts(tx, actor.UserID, userID, -1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return followStatus(actor.UserID, userID)
+}
+
+// RemoveFollows deletes a user's follows in both directions, fixing the counts of
+// the users on the other side
+func RemoveFollows(tx *gorm.DB, userID uint) error {
+	followees := tx.Model(&models.Follow{}).Select("followee_id").Where("follower_id = ?", userID)
+	if err := tx.Model(&models.User{}).Where("id IN (?)", followees).
+		UpdateColumn("followers_count", gorm.Expr("GREATEST(followers_count - 1, 0)")).Error; err != nil {
+		return err
+	}
+	followers := tx.Model(&models.Follow{}).Select("follower_id").Where("followee_id = ?", userID)
+	if err := tx.Model(&models.User{}).Where("id IN (?)", followers).
+		UpdateColumn("following_count", gorm.Expr("GREATEST(following_count - 1, 0)")).Error; err != nil {
+		return err
+	}
+	return tx.Where("follower_id = ? OR followee_id = ?", userID, userID).Delete(&models.Follow{}).Error
+}
+
+// GetPublicProfile returns the public profile of an active user
+func GetPublicProfile(viewer Viewer, userID uint) (*models.PublicProfile, error) {
+	user, err := findActiveUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	followed, err := isFollowing(viewer.UserID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PublicProfile{
+		UserSummary:    summarize(*user),
+		Bio:            user.Bio,
+		TotalCreations: user.TotalCreations,
+		TotalLikes:     user.TotalLikes,
+		TrendingScore:  user.TrendingScore,
+		CommunityRank:  user.CommunityRank,
+		FollowersCount: user.FollowersCount,
+		FollowingCount: user.FollowingCount,
+		FollowedByMe:   followed,
+		CreatedAt:      user.CreatedAt,
+	}, nil
+}
+
+// ListFollowers returns one page of the active users following a user
+func ListFollowers(userID uint, page utils.PageRequest) ([]models.FollowEntry, *utils.Pagination, error) {
+	return listFollows("followee_id", "follower_id", userID, page)
+}
+
+// ListFollowing returns one page of the active users a user follows
+func ListFollowing(userID uint, page utils.PageRequest) ([]models.FollowEntry, *utils.Pagination, error) {
+	return listFollows("follower_id", "followee_id", userID, page)
+}
+
+// listFollows pages through the follows whose column matches the user and loads the
+// users on the other side
+func listFollows(column, otherColumn string, userID uint, page utils.PageRequest) ([]models.FollowEntry, *utils.Pagination, error) {
+	if _, err := findActiveUser(userID); err != nil {
+		return nil, nil, err
+	}
+
+	follows := []models.Follow{}
+	pagination, err := utils.Paginate(config.DB.Model(&models.Follow{}).Where(column+" = ?", userID), page, &follows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	otherIDs := make([]uint, len(follows))
+	for i, follow := range follows {
+		otherIDs[i] = follow.FolloweeID
+		if otherColumn == "follower_id" {
+			otherIDs[i] = follow.FollowerID
+		}
+	}
+	var users []models.User
+	if len(otherIDs) > 0 {
+		if err := config.DB.Where("id IN ? AND is_active = ?", otherIDs, true).Find(&users).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	byID := make(map[uint]models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	entries := make([]models.FollowEntry, 0, len(follows))
+	for i, follow := range follows {
+		user, ok := byID[otherIDs[i]]
+		if !ok {
+			continue
+		}
+		entries = append(entries, models.FollowEntry{User: summarize(user), FollowedAt: follow.CreatedAt})
+	}
+	return entries, pagination, nil
+}
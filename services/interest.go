@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownTags is returned when interests name tags that don't exist or are inactive
+var ErrUnknownTags = errors.New("unknown or inactive tags")
+
+// GetInterests returns the tags the user picked as interests
+func GetInterests(userID uint) ([]models.Tag, error) {
+	user := models.User{ID: userID}
+	tags := []models.Tag{}
+	err := config.DB.Model(&user).Association("Interests").Find(&tags)
+	return tags, err
+}
+
+// SetInterests replaces the user's interests with the given active tags
+func SetInterests(userID uint, tagIDs []uint) ([]models.Tag, error) {
+	unique := make([]uint, 0, len(tagIDs))
+	seen := map[uint]bool{}
+	for _, id := range tagIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	tags := []models.Tag{}
+	if len(unique) > 0 {
+		if err := config.DB.Where("id IN ? AND is_active = ?", unique, true).Find(&tags).Error; err != nil {
+			return nil, err
+		}
+	}
+	if len(tags) != len(unique) {
+		found := map[uint]bool{}
+		for _, tag := range tags {
+			found[tag.ID] = true
+		}
+		var missing []uint
+		for _, id := range unique {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnknownTags, missing)
+	}
+
+	user := models.User{ID: userID}
+	if err := config.DB.Model(&user).Association("Interests").Replace(tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ClearInterests unlinks a user that is being deleted from their interests
+func ClearInterests(tx *gorm.DB, userID uint) error {
+	return tx.Exec("DELETE FROM user_interests WHERE user_id = ?", userID).Error
+}
+
+// RemoveTagFromInterests unlinks a tag that is being deleted from every user's interests
+func RemoveTagFromInterests(tx *gorm.DB, tagID uint) error {
+	return tx.Exec("DELETE FROM user_interests WHERE tag_id = ?", tagID).Error
+}
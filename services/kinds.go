@@ -0,0 +1,133 @@
+package services
+
+import (
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+)
+
+// Images manages image prompts stored in Cloudinary
+var Images = NewPromptService[models.ImagePrompt](MediaKind{
+	Name:               "image",
+	Label:              "Image",
+	Noun:               "image",
+	FormField:          "image",
+	ContentTypePrefix:  "image/",
+	MissingFileMessage: "No image file provided",
+	InvalidTypeMessage: "File must be an image",
+	Storage:            CloudinaryStorage{},
+})
+
+// GIFs manages GIF prompts stored in Backblaze B2
+var GIFs = NewPromptService[models.GIFPrompt](MediaKind{
+	Name:               "gif",
+	Label:              "GIF",
+	Noun:               "GIF",
+	FormField:          "gif",
+	ContentTypePrefix:  "image/gif",
+	MissingFileMessage: "GIF file is required",
+	InvalidTypeMessage: "Only GIF files are allowed",
+	Storage: B2Storage{
+		Folder: "gifs",
+		Bucket: func(cfg *config.Config) string { return cfg.B2S3BucketGIF },
+	},
+})
+
+// Videos manages video prompts stored in Backblaze B2
+var Videos = NewPromptService[models.VideoPrompt](MediaKind{
+	Name:               "video",
+	Label:              "Video",
+	Noun:               "video",
+	FormField:          "video",
+	ContentTypePrefix:  "video/",
+	MissingFileMessage: "Video file is required",
+	InvalidTypeMessage: "Only video files are allowed",
+	Storage: B2Storage{
+		Folder: "videos",
+		Bucket: func(cfg *config.Config) string { return cfg.B2S3BucketVideo },
+	},
+})
+
+// KindService is the part of a prompt service that doesn't depend on the model
+// type, letting features span every media kind
+type KindService interface {
+	MediaKind() MediaKind
+	Table() string
+	TagJoin() (table, column string)
+	FindBase(id uint) (*models.PromptBase, error)
+	FindVisible(viewer Viewer, ids []uint) ([]models.MediaPrompt, error)
+	Apply(actor Actor, id uint, op BulkOperation) error
+}
+
+// Kinds lists the service of every media kind
+var Kinds = []KindService{Images, GIFs, Videos}
+
+// LookupKind returns the service for a media kind name such as "image"
+func LookupKind(name string) (KindService, bool) {
+	for _, kind := range Kinds {
+		if kind.MediaKind().Name == name {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// PromptRef identifies a prompt of any media kind
+type PromptRef struct {
+	Kind string
+	ID   uint
+}
+
+// visibleRefs reports which of the referenced prompts exist and are visible to the
+// viewer, without loading them
+func visibleRefs(viewer Viewer, refs []PromptRef) (map[PromptRef]bool, error) {
+	idsByKind := map[string][]uint{}
+	for _, ref := range refs {
+		idsByKind[ref.Kind] = append(idsByKind[ref.Kind], ref.ID)
+	}
+
+	visible := map[PromptRef]bool{}
+	for name, ids := range idsByKind {
+		kind, ok := LookupKind(name)
+		if !ok {
+			continue
+		}
+		var found []uint
+		if err := viewer.Scope(config.DB.Table(kind.Table())).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range found {
+			visible[PromptRef{Kind: name, ID: id}] = true
+		}
+	}
+	return visible, nil
+}
+
+// LoadPrompts loads the referenced prompts with their user and tags, in order.
+// Entries the viewer may not see, or that no longer exist, are nil.
+func LoadPrompts(viewer Viewer, refs []PromptRef) ([]models.MediaPrompt, error) {
+	idsByKind := map[string][]uint{}
+	for _, ref := range refs {
+		idsByKind[ref.Kind] = append(idsByKind[ref.Kind], ref.ID)
+	}
+
+	loaded := map[PromptRef]models.MediaPrompt{}
+	for name, ids := range idsByKind {
+		kind, ok := LookupKind(name)
+		if !ok {
+			continue
+		}
+		prompts, err := kind.FindVisible(viewer, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, prompt := range prompts {
+			loaded[PromptRef{Kind: name, ID: prompt.Base().ID}] = prompt
+		}
+	}
+
+	result := make([]models.MediaPrompt, len(refs))
+	for i, ref := range refs {
+		result[i] = loaded[ref]
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LikeSortKeys are the orderings accepted by the likes listing
+var LikeSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+}
+
+// Like records the viewer's like of a visible prompt and bumps the prompt's and its
+// creator's counters. Liking twice is a no-op.
+func (s *PromptService[T, PT]) Like(viewer Viewer, id uint) (*models.LikeStatus, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	base := prompt.Base()
+	if !viewer.CanView(base) {
+		return nil, ErrNotFound
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		like := models.Like{UserID: viewer.UserID, TargetType: s.Kind.Name, TargetID: id}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return s.adjustLikes(tx, base, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.likeStatus(viewer.UserID, id)
+}
+
+// Unlike removes the viewer's like of a prompt and decrements the counters.
+// Unliking a prompt that isn't liked is a no-op.
+func (s *PromptService[T, PT]) Unlike(viewer Viewer, id uint) (*models.LikeStatus, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	base := prompt.Base()
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND target_type = ? AND target_id = ?", viewer.UserID, s.Kind.Name, id).
+			Delete(&models.Like{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return s.adjustLikes(tx, base, -1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.likeStatus(viewer.UserID, id)
+}
+
+// adjustLikes atomically moves a prompt's likes count and its creator's total likes by delta
+func (s *PromptService[T, PT]) adjustLikes(tx *gorm.DB, base *models.PromptBase, delta int) error {
+	if err := tx.Model(PT(new(T))).Where("id = ?", base.ID).
+		UpdateColumn("likes_count", gorm.Expr("GREATEST(likes_count + ?, 0)", delta)).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", base.UserID).
+		UpdateColumn("total_likes", gorm.Expr("GREATEST(total_likes + ?, 0)", delta)).Error
+}
+
+// likeStatus reads the current likes count of a prompt and whether the user likes it
+func (s *PromptService[T, PT]) likeStatus(userID, id uint) (*models.LikeStatus, error) {
+	status := &models.LikeStatus{}
+	if err := config.DB.Model(PT(new(T))).Select("likes_count").Where("id = ?", id).
+		Row().Scan(&status.LikesCount); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.Like{}).
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, s.Kind.Name, id).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	status.Liked = count > 0
+	return status, nil
+}
+
+// markLiked sets LikedByMe on the prompts the user has liked
+func (s *PromptService[T, PT]) markLiked(userID uint, prompts []PT) {
+	if userID == 0 || len(prompts) == 0 {
+		return
+	}
+
+	ids := make([]uint, len(prompts))
+	for i, prompt := range prompts {
+		ids[i] = prompt.Base().ID
+	}
+
+	var liked []uint
+	if err := config.DB.Model(&models.Like{}).
+		Where("user_id = ? AND target_type = ? AND target_id IN ?", userID, s.Kind.Name, ids).
+		Pluck("target_id", &liked).Error; err != nil {
+		return
+	}
+
+	likedSet := make(map[uint]bool, len(liked))
+	for _, id := range liked {
+		likedSet[id] = true
+	}
+	for _, prompt := range prompts {
+		prompt.Base().LikedByMe = likedSet[prompt.Base().ID]
+	}
+}
+
+// ListLikes returns one page of the prompts the viewer has liked, most recent like
+// first. Prompts that have since been hidden from the viewer are left out.
+func ListLikes(viewer Viewer, page utils.PageRequest) ([]models.LikedPrompt, *utils.Pagination, error) {
+	likes := []models.Like{}
+	pagination, err := utils.Paginate(config.DB.Model(&models.Like{}).Where("user_id = ?", viewer.UserID), page, &likes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]PromptRef, len(likes))
+	for i, like := range likes {
+		refs[i] = PromptRef{Kind: like.TargetType, ID: like.TargetID}
+	}
+	prompts, err := LoadPrompts(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]models.LikedPrompt, 0, len(likes))
+	for i, like := range likes {
+		if prompts[i] == nil {
+			continue
+		}
+		items = append(items, models.LikedPrompt{Kind: like.TargetType, LikedAt: like.CreatedAt, Prompt: prompts[i]})
+	}
+	return items, pagination, nil
+}
+
+// RemoveUserLikes deletes the likes of a user that is being deleted, taking them off
+// the liked prompts' counts and their creators' totals
+func RemoveUserLikes(tx *gorm.DB, userID uint) error {
+	for _, kind := range Kinds {
+		name, table := kind.MediaKind().Name, kind.Table()
+		liked := tx.Model(&models.Like{}).Select("target_id").Where("user_id = ? AND target_type = ?", userID, name)
+		if err := tx.Table(table).Where("id IN (?)", liked).
+			UpdateColumn("likes_count", gorm.Expr("GREATEST(likes_count - 1, 0)")).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE users JOIN (SELECT "+table+".user_id, COUNT(*) AS likes FROM likes "+
+			"JOIN "+table+" ON "+table+".id = likes.target_id WHERE likes.user_id = ? AND likes.target_type = ? "+
+			"GROUP BY "+table+".user_id) AS liked ON liked.user_id = users.id "+
+			"SET users.total_likes = GREATEST(users.total_likes - liked.likes, 0)", userID, name).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("user_id = ?", userID).Delete(&models.Like{}).Error
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Decision carries a moderator's reasoning for a status change
+type Decision struct {
+	Reason  string // Canned rejection reason
+	Details string // Free text shown to the owner
+	Note    string // Internal note for moderators only
+}
+
+// IsRejectionReason reports whether reason is one of the configured canned reasons
+func IsRejectionReason(reason string) bool {
+	for _, r := range config.AppConfig.RejectionReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// addNote stores an internal moderator note on a prompt
+func addNote(tx *gorm.DB, actor Actor, kind string, targetID uint, text string) (*models.ModerationNote, error) {
+	note := models.ModerationNote{
+		TargetType:     kind,
+		TargetID:       targetID,
+		AuthorID:       actor.UserID,
+		AuthorUsername: actor.Username,
+		Note:           text,
+	}
+	if err := tx.Create(&note).Error; err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// Notes returns a prompt's moderator notes, oldest first (Moderators only)
+func (s *PromptService[T, PT]) Notes(id uint) ([]models.ModerationNote, error) {
+	if _, err := s.find(id); err != nil {
+		return nil, err
+	}
+
+	notes := []models.ModerationNote{}
+	if err := config.DB.Where("target_type = ? AND target_id = ?", s.Kind.Name, id).
+		Order("created_at ASC, id ASC").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// AddNote attaches an internal note to a prompt (Moderators only)
+func (s *PromptService[T, PT]) AddNote(actor Actor, id uint, text string) (*models.ModerationNote, error) {
+	if _, err := s.find(id); err != nil {
+		return nil, err
+	}
+	return addNote(config.DB, actor, s.Kind.Name, id, text)
+}
+
+// recordModerationEvent appends a step to a prompt's review history
+func recordModerationEvent(tx *gorm.DB, actor Actor, kind string, targetID uint, action, from, to string, decision Decision) error {
+	return tx.Create(&models.ModerationEvent{
+		TargetType:    kind,
+		TargetID:      targetID,
+		ActorID:       actor.UserID,
+		ActorUsername: actor.Username,
+		Action:        action,
+		FromStatus:    from,
+		ToStatus:      to,
+		Reason:        decision.Reason,
+		Details:       decision.Details,
+	}).Error
+}
+
+// History returns a prompt's review history, oldest first (Moderators only)
+func (s *PromptService[T, PT]) History(id uint) ([]models.ModerationEvent, error) {
+	if _, err := s.find(id); err != nil {
+		return nil, err
+	}
+
+	events := []models.ModerationEvent{}
+	if err := config.DB.Where("target_type = ? AND target_id = ?", s.Kind.Name, id).
+		Order("created_at ASC, id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
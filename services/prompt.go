@@ -0,0 +1,661 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PromptModel is satisfied by pointers to the media prompt models
+type PromptModel[T any] interface {
+	*T
+	models.MediaPrompt
+}
+
+// MediaKind describes a media kind and how its assets are stored
+type MediaKind struct {
+	Name               string // Stable identifier, e.g. "image"
+	Label              string // Capitalised label used in messages, e.g. "Image"
+	Noun               string // Lowercase label used in messages, e.g. "image"
+	FormField          string // Multipart field carrying the file
+	ContentTypePrefix  string // Accepted Content-Type prefix
+	MissingFileMessage string
+	InvalidTypeMessage string
+	Storage            MediaStorage
+}
+
+// PromptInput holds the submitted fields of a new prompt
+type PromptInput struct {
+	ProjectTitle   string
+	Prompt         string
+	TechnicalNotes string
+	ModelOrTool    string
+	CreatorCredit  string
+	TagIDs         []string
+}
+
+// PromptFilter holds the list filters shared by all media kinds
+type PromptFilter struct {
+	Status     string
+	UserID     string
+	IsFeatured bool
+}
+
+// counterColumns are the engagement counters updated in place rather than saved
+var counterColumns = []string{"likes_count", "views_count", "downloads_count", "comments_count"}
+
+// PromptSortKeys are the orderings accepted by the prompt list endpoints of every
+// kind; SortKeys adds the per-kind ones
+var PromptSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+	{Name: "likes_count", Expr: "likes_count"},
+	{Name: "views_count", Expr: "views_count"},
+	{Name: "downloads_count", Expr: "downloads_count"},
+}
+
+// statusTransitions lists the statuses each status may move to
+var statusTransitions = map[string][]string{
+	models.PromptStatusPending:  {models.PromptStatusApproved, models.PromptStatusRejected},
+	models.PromptStatusApproved: {models.PromptStatusRejected},
+	models.PromptStatusRejected: {models.PromptStatusApproved, models.PromptStatusPending}, // Pending again on resubmission
+}
+
+// CanTransition reports whether a prompt may move between two statuses
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManage reports whether a user may edit or delete a prompt
+func CanManage(prompt *models.PromptBase, userID uint, role string) bool {
+	return models.HasPermission(role, models.PermPromptsManage) || prompt.UserID == userID
+}
+
+// PromptService implements the prompt lifecycle for one media kind
+type PromptService[T any, PT PromptModel[T]] struct {
+	Kind MediaKind
+}
+
+// NewPromptService creates a prompt service for a media kind
+func NewPromptService[T any, PT PromptModel[T]](kind MediaKind) *PromptService[T, PT] {
+	return &PromptService[T, PT]{Kind: kind}
+}
+
+// SortKeys returns the orderings accepted by the kind's list endpoints. trending
+// orders by the computed daily score, as /trending does; unranked prompts score 0.
+func (s *PromptService[T, PT]) SortKeys() []utils.SortKey {
+	trending := fmt.Sprintf("COALESCE((SELECT trending_scores.score FROM trending_scores "+
+		"WHERE trending_scores.score_window = '%s' AND trending_scores.target_type = '%s' "+
+		"AND trending_scores.target_id = %s.id), 0)", models.WindowDaily, s.Kind.Name, s.Table())
+	return append(append([]utils.SortKey{}, PromptSortKeys...), utils.SortKey{Name: "trending", Expr: trending})
+}
+
+func (s *PromptService[T, PT]) withRelations() *gorm.DB {
+	return config.DB.Preload("User").Preload("Tags")
+}
+
+// find loads a prompt without relations
+func (s *PromptService[T, PT]) find(id uint) (PT, error) {
+	prompt := PT(new(T))
+	if err := config.DB.First(prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return prompt, nil
+}
+
+// reload loads a prompt with its user and tags and signs its media URL
+func (s *PromptService[T, PT]) reload(prompt PT) (PT, error) {
+	fresh := PT(new(T))
+	if err := s.withRelations().First(fresh, prompt.Base().ID).Error; err != nil {
+		return nil, err
+	}
+	s.present(fresh)
+	return fresh, nil
+}
+
+// present readies a loaded prompt for a response: it signs the media URL and swaps
+// the creator for their public summary
+func (s *PromptService[T, PT]) present(prompt PT) {
+	s.signURL(prompt)
+	if owner := prompt.Owner(); owner.ID != 0 {
+		author := summarize(*owner)
+		prompt.Base().Author = &author
+	}
+}
+
+func (s *PromptService[T, PT]) signURL(prompt PT) {
+	if prompt.MediaURL() == "" {
+		return
+	}
+	signedURL, err := s.Kind.Storage.SignURL(prompt.MediaURL())
+	if err != nil {
+		log.Printf("⚠️  Failed to generate signed URL for %s %d: %v", s.Kind.Noun, prompt.Base().ID, err)
+		return
+	}
+	prompt.SetMediaURL(signedURL)
+}
+
+// Create validates and uploads a new submission and stores it as pending
+func (s *PromptService[T, PT]) Create(userID uint, input PromptInput, file multipart.File, header *multipart.FileHeader) (PT, error) {
+	if input.ProjectTitle == "" || input.Prompt == "" || input.CreatorCredit == "" {
+		return nil, ErrMissingFields
+	}
+
+	url, err := s.upload(file, header)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := PT(new(T))
+	base := prompt.Base()
+	base.UserID = userID
+	base.ProjectTitle = input.ProjectTitle
+	base.Prompt = input.Prompt
+	base.TechnicalNotes = input.TechnicalNotes
+	base.ModelOrTool = input.ModelOrTool
+	base.CreatorCredit = input.CreatorCredit
+	base.Status = models.PromptStatusPending
+	size := int(header.Size)
+	prompt.SetMedia(url, header.Filename, &size)
+
+	if err := config.DB.Create(prompt).Error; err != nil {
+		// Don't leave an orphaned asset behind
+		if delErr := s.Kind.Storage.Delete(url); delErr != nil {
+			log.Printf("⚠️  Failed to clean up %s asset: %v", s.Kind.Noun, delErr)
+		}
+		return nil, err
+	}
+
+	if err := s.attachTags(prompt, input.TagIDs); err != nil {
+		log.Printf("⚠️  Failed to attach tags to %s %d: %v", s.Kind.Noun, base.ID, err)
+	}
+
+	submitter := Actor{UserID: userID}
+	if err := recordModerationEvent(config.DB, submitter, s.Kind.Name, base.ID, models.ModerationSubmit, "", models.PromptStatusPending, Decision{}); err != nil {
+		log.Printf("⚠️  Failed to record submission of %s %d: %v", s.Kind.Noun, base.ID, err)
+	}
+
+	return s.reload(prompt)
+}
+
+// upload checks a file's content type and stores it
+func (s *PromptService[T, PT]) upload(file multipart.File, header *multipart.FileHeader) (string, error) {
+	if !strings.HasPrefix(header.Header.Get("Content-Type"), s.Kind.ContentTypePrefix) {
+		return "", ErrInvalidMediaType
+	}
+
+	url, err := s.Kind.Storage.Upload(file, header)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUploadFailed, err)
+	}
+	return url, nil
+}
+
+// Resubmit lets the owner of a rejected prompt edit its fields, optionally replace
+// its media and send it back for review. file may be nil to keep the current media;
+// nil TagIDs keep the current tags.
+func (s *PromptService[T, PT]) Resubmit(actor Actor, id uint, input PromptInput, file multipart.File, header *multipart.FileHeader) (PT, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := prompt.Base()
+	if base.UserID != actor.UserID {
+		return nil, ErrForbidden
+	}
+	if base.Status != models.PromptStatusRejected || !CanTransition(base.Status, models.PromptStatusPending) {
+		return nil, ErrInvalidTransition
+	}
+
+	var newURL string
+	if file != nil {
+		if newURL, err = s.upload(file, header); err != nil {
+			return nil, err
+		}
+	}
+	oldURL := prompt.MediaURL()
+
+	if input.ProjectTitle != "" {
+		base.ProjectTitle = input.ProjectTitle
+	}
+	if input.Prompt != "" {
+		base.Prompt = input.Prompt
+	}
+	if input.TechnicalNotes != "" {
+		base.TechnicalNotes = input.TechnicalNotes
+	}
+	if input.ModelOrTool != "" {
+		base.ModelOrTool = input.ModelOrTool
+	}
+	if input.CreatorCredit != "" {
+		base.CreatorCredit = input.CreatorCredit
+	}
+	if newURL != "" {
+		size := int(header.Size)
+		prompt.SetMedia(newURL, header.Filename, &size)
+	}
+
+	// The previous decision lives on in the moderation history
+	base.Status = models.PromptStatusPending
+	base.VerifiedBy = nil
+	base.VerifiedAt = nil
+	base.RejectionReason = ""
+	base.RejectionDetails = ""
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(counterColumns...).Save(prompt).Error; err != nil {
+			return err
+		}
+		if input.TagIDs != nil {
+			if err := s.replaceTags(tx, prompt, input.TagIDs); err != nil {
+				return err
+			}
+		}
+		return recordModerationEvent(tx, actor, s.Kind.Name, base.ID, models.ModerationResubmit,
+			models.PromptStatusRejected, models.PromptStatusPending, Decision{})
+	})
+	if err != nil {
+		if newURL != "" {
+			// Don't leave an orphaned asset behind
+			if delErr := s.Kind.Storage.Delete(newURL); delErr != nil {
+				log.Printf("⚠️  Failed to clean up %s asset: %v", s.Kind.Noun, delErr)
+			}
+		}
+		return nil, err
+	}
+
+	if newURL != "" {
+		if err := s.Kind.Storage.Delete(oldURL); err != nil {
+			log.Printf("⚠️  Failed to delete replaced %s asset: %v", s.Kind.Noun, err)
+		}
+	}
+
+	return s.reload(prompt)
+}
+
+func (s *PromptService[T, PT]) attachTags(prompt PT, tagIDs []string) error {
+	tags, err := findTags(config.DB, tagIDs)
+	if err != nil || len(tags) == 0 {
+		return err
+	}
+	return config.DB.Model(prompt).Association("Tags").Append(tags)
+}
+
+// replaceTags sets a prompt's tags to exactly the given tag IDs
+func (s *PromptService[T, PT]) replaceTags(tx *gorm.DB, prompt PT, tagIDs []string) error {
+	tags, err := findTags(tx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return tx.Model(prompt).Association("Tags").Clear()
+	}
+	return tx.Model(prompt).Association("Tags").Replace(tags)
+}
+
+// findTags loads the tags with the given IDs, ignoring blanks and unknown IDs
+func findTags(db *gorm.DB, tagIDs []string) ([]models.Tag, error) {
+	ids := make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var tags []models.Tag
+	if err := db.Where("id IN ?", ids).Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// List returns one page of the prompts visible to the viewer that match the filter
+func (s *PromptService[T, PT]) List(viewer Viewer, filter PromptFilter, page utils.PageRequest) ([]T, *utils.Pagination, error) {
+	query := viewer.Scope(config.DB.Model(PT(new(T))))
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.IsFeatured {
+		query = query.Where("is_featured = ?", true)
+	}
+
+	prompts := []T{}
+	pagination, err := utils.Paginate(query, page, &prompts, "User", "Tags")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.prepare(viewer, prompts)
+	return prompts, pagination, nil
+}
+
+// prepare presents loaded prompts, adds unflushed view and download
+// counts and flags the viewer's likes
+func (s *PromptService[T, PT]) prepare(viewer Viewer, prompts []T) {
+	ptrs := make([]PT, len(prompts))
+	for i := range prompts {
+		ptrs[i] = PT(&prompts[i])
+		s.present(ptrs[i])
+		s.withPendingCounts(ptrs[i].Base())
+	}
+	s.markLiked(viewer.UserID, ptrs)
+}
+
+// FindVisible loads the prompts with the given IDs that the viewer may see, with
+// their user and tags
+func (s *PromptService[T, PT]) FindVisible(viewer Viewer, ids []uint) ([]models.MediaPrompt, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	prompts := []T{}
+	if err := viewer.Scope(s.withRelations().Model(PT(new(T)))).Where("id IN ?", ids).Find(&prompts).Error; err != nil {
+		return nil, err
+	}
+	s.prepare(viewer, prompts)
+
+	result := make([]models.MediaPrompt, len(prompts))
+	for i := range prompts {
+		result[i] = PT(&prompts[i])
+	}
+	return result, nil
+}
+
+// Get returns a single prompt with its user and tags. Prompts hidden from the
+// viewer are reported as not found.
+func (s *PromptService[T, PT]) Get(viewer Viewer, id uint) (PT, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanView(prompt.Base()) {
+		return nil, ErrNotFound
+	}
+
+	prompt, err = s.reload(prompt)
+	if err != nil {
+		return nil, err
+	}
+	s.withPendingCounts(prompt.Base())
+	s.markLiked(viewer.UserID, []PT{prompt})
+	return prompt, nil
+}
+
+// Delete removes a prompt and its asset (Admin or Owner)
+func (s *PromptService[T, PT]) Delete(actor Actor, id uint) error {
+	prompt, err := s.find(id)
+	if err != nil {
+		return err
+	}
+
+	base := prompt.Base()
+	if !CanManage(base, actor.UserID, actor.Role) {
+		return ErrForbidden
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("Tags").Delete(prompt).Error; err != nil {
+			return err
+		}
+		if err := deletePromptComments(tx, s.Kind.Name, base.ID); err != nil {
+			return err
+		}
+		if err := removePromptFromCollections(tx, s.Kind.Name, base.ID); err != nil {
+			return err
+		}
+		// Take the prompt's likes off its creator's total, counting the rows actually
+		// removed since likes may have landed after the prompt was loaded
+		likes := tx.Where("target_type = ? AND target_id = ?", s.Kind.Name, base.ID).Delete(&models.Like{})
+		if likes.Error != nil {
+			return likes.Error
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", base.UserID).
+			UpdateColumn("total_likes", gorm.Expr("GREATEST(total_likes - ?, 0)", likes.RowsAffected)).Error; err != nil {
+			return err
+		}
+		if base.UserID == actor.UserID {
+			return nil // Owners removing their own work isn't a staff action
+		}
+		return RecordAudit(tx, actor, AuditPromptDelete, s.Kind.Name, base.ID, base, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The asset goes only once the row is gone, so a failed delete leaves a working prompt
+	if err := s.Kind.Storage.Delete(prompt.MediaURL()); err != nil {
+		log.Printf("⚠️  Failed to delete %s asset: %v", s.Kind.Noun, err)
+	}
+	return nil
+}
+
+// save stores a changed prompt and audits the change in one transaction, running
+// any extra writes in the same transaction. An empty action skips the audit.
+func (s *PromptService[T, PT]) save(actor Actor, action string, prompt PT, before models.PromptBase, extra ...func(tx *gorm.DB) error) (PT, error) {
+	return s.saveGuarded(actor, action, prompt, before, nil, extra...)
+}
+
+// saveGuarded is save with a guard run first in the transaction; an error from the
+// guard aborts the save
+func (s *PromptService[T, PT]) saveGuarded(actor Actor, action string, prompt PT, before models.PromptBase, guard func(tx *gorm.DB) error, extra ...func(tx *gorm.DB) error) (PT, error) {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if guard != nil {
+			if err := guard(tx); err != nil {
+				return err
+			}
+		}
+		// Counters move atomically elsewhere; writing back the loaded values would lose hits
+		if err := tx.Omit(counterColumns...).Save(prompt).Error; err != nil {
+			return err
+		}
+		for _, fn := range extra {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+		if action == "" {
+			return nil
+		}
+		return RecordAudit(tx, actor, action, s.Kind.Name, before.ID, before, prompt.Base())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.reload(prompt)
+}
+
+// setStatus moves a prompt through the moderation status machine, recording who
+// verified it and why
+func (s *PromptService[T, PT]) setStatus(actor Actor, id uint, status, action string, decision Decision) (PT, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := prompt.Base()
+	before := *base
+	if !CanTransition(base.Status, status) {
+		return nil, ErrInvalidTransition
+	}
+
+	now := time.Now()
+	verifiedBy := actor.UserID
+	base.Status = status
+	base.VerifiedBy = &verifiedBy
+	base.VerifiedAt = &now
+	base.RejectionReason = decision.Reason
+	base.RejectionDetails = decision.Details
+	if status == models.PromptStatusRejected {
+		base.IsPublished = false
+	}
+
+	moderationAction := models.ModerationApprove
+	if status == models.PromptStatusRejected {
+		moderationAction = models.ModerationReject
+	}
+	extra := []func(tx *gorm.DB) error{
+		func(tx *gorm.DB) error {
+			return recordModerationEvent(tx, actor, s.Kind.Name, base.ID, moderationAction, before.Status, status, decision)
+		},
+		func(tx *gorm.DB) error {
+			return clearClaim(tx, s.Kind.Name, base.ID)
+		},
+	}
+	if decision.Note != "" {
+		extra = append(extra, func(tx *gorm.DB) error {
+			_, err := addNote(tx, actor, s.Kind.Name, base.ID, decision.Note)
+			return err
+		})
+	}
+	// Lock the prompt and its claim so a concurrent decision or claim waits for this
+	// one, then recheck both under the lock
+	guard := func(tx *gorm.DB) error {
+		var current []string
+		if err := tx.Table(s.Table()).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", base.ID).Pluck("status", &current).Error; err != nil {
+			return err
+		}
+		if len(current) == 0 {
+			return ErrNotFound
+		}
+		if !CanTransition(current[0], status) {
+			return ErrInvalidTransition
+		}
+		return checkClaim(tx.Clauses(clause.Locking{Strength: "UPDATE"}), actor, s.Kind.Name, base.ID)
+	}
+	return s.saveGuarded(actor, action, prompt, before, guard, extra...)
+}
+
+// Approve approves a pending or rejected prompt, clearing any rejection reason (Admin only)
+func (s *PromptService[T, PT]) Approve(actor Actor, id uint) (PT, error) {
+	return s.setStatus(actor, id, models.PromptStatusApproved, AuditPromptApprove, Decision{})
+}
+
+// Reject rejects a prompt with one of the configured reasons and takes it offline (Admin only)
+func (s *PromptService[T, PT]) Reject(actor Actor, id uint, decision Decision) (PT, error) {
+	if !IsRejectionReason(decision.Reason) {
+		return nil, ErrInvalidReason
+	}
+	return s.setStatus(actor, id, models.PromptStatusRejected, AuditPromptReject, decision)
+}
+
+// setPublished toggles the published flag of a prompt
+func (s *PromptService[T, PT]) setPublished(actor Actor, id uint, published bool) (PT, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := prompt.Base()
+	before := *base
+	if published && base.Status != models.PromptStatusApproved {
+		return nil, ErrNotApproved
+	}
+	base.IsPublished = published
+	if published && base.PublishedAt == nil {
+		now := time.Now()
+		base.PublishedAt = &now
+	}
+
+	action := AuditPromptUnpublish
+	if published {
+		action = AuditPromptPublish
+	}
+	return s.save(actor, action, prompt, before)
+}
+
+// Publish publishes an approved prompt (Admin only)
+func (s *PromptService[T, PT]) Publish(actor Actor, id uint) (PT, error) {
+	return s.setPublished(actor, id, true)
+}
+
+// Unpublish takes a prompt offline (Admin only)
+func (s *PromptService[T, PT]) Unpublish(actor Actor, id uint) (PT, error) {
+	return s.setPublished(actor, id, false)
+}
+
+// SetFeatured features or unfeatures a prompt (prompt managers only)
+func (s *PromptService[T, PT]) SetFeatured(actor Actor, id uint, featured bool) (PT, error) {
+	if !models.HasPermission(actor.Role, models.PermPromptsManage) {
+		return nil, ErrForbidden
+	}
+
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := prompt.Base()
+	before := *base
+	base.IsFeatured = featured
+
+	action := AuditPromptUnfeature
+	if featured {
+		action = AuditPromptFeature
+	}
+	return s.save(actor, action, prompt, before)
+}
+
+// Update edits a prompt's text fields (Admin or Owner); only prompt managers may feature
+func (s *PromptService[T, PT]) Update(actor Actor, id uint, req models.UpdatePromptRequest) (PT, error) {
+	prompt, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := prompt.Base()
+	before := *base
+	if !CanManage(base, actor.UserID, actor.Role) {
+		return nil, ErrForbidden
+	}
+
+	if req.ProjectTitle != "" {
+		base.ProjectTitle = req.ProjectTitle
+	}
+	if req.Prompt != "" {
+		base.Prompt = req.Prompt
+	}
+	if req.TechnicalNotes != "" {
+		base.TechnicalNotes = req.TechnicalNotes
+	}
+	if req.ModelOrTool != "" {
+		base.ModelOrTool = req.ModelOrTool
+	}
+	if req.CreatorCredit != "" {
+		base.CreatorCredit = req.CreatorCredit
+	}
+	if req.IsFeatured != nil && models.HasPermission(actor.Role, models.PermPromptsManage) {
+		base.IsFeatured = *req.IsFeatured
+	}
+
+	action := AuditPromptUpdate
+	if base.UserID == actor.UserID {
+		action = "" // Owners editing their own work isn't a staff action
+	}
+	return s.save(actor, action, prompt, before)
+}
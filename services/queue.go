@@ -0,0 +1,250 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrClaimed is returned when another moderator holds an active claim on a prompt
+var ErrClaimed = errors.New("prompt is claimed by another moderator")
+
+// QueueFilter holds the filters accepted by the moderation queue
+type QueueFilter struct {
+	Kind      string // Media kind name; empty for all kinds
+	Unclaimed bool   // Only items nobody has claimed
+	ClaimedBy uint   // Only items claimed by this moderator
+}
+
+// pendingUnion selects the pending prompts of every media kind as one relation
+// with the columns kind, id, user_id, project_title and created_at
+func pendingUnion() *gorm.DB {
+	parts := make([]string, 0, len(Kinds))
+	args := make([]interface{}, 0, len(Kinds))
+	for _, kind := range Kinds {
+		parts = append(parts, "(?)")
+		args = append(args, config.DB.Table(kind.Table()).
+			Select("? AS kind, id, user_id, project_title, created_at", kind.MediaKind().Name).
+			Where("status = ?", models.PromptStatusPending))
+	}
+	return config.DB.Raw(strings.Join(parts, " UNION ALL "), args...)
+}
+
+// queueQuery joins the pending prompts with their active claims
+func queueQuery(now time.Time) *gorm.DB {
+	return config.DB.Table("(?) AS queue", pendingUnion()).
+		Joins("LEFT JOIN moderation_claims AS claims ON claims.target_type = queue.kind AND claims.target_id = queue.id AND claims.expires_at > ?", now)
+}
+
+// ListQueue returns one page of pending prompts of every kind, oldest first
+func ListQueue(filter QueueFilter, limit, page int) ([]models.QueueItem, int64, error) {
+	now := time.Now()
+	query := queueQuery(now)
+
+	if filter.Kind != "" {
+		query = query.Where("queue.kind = ?", filter.Kind)
+	}
+	if filter.Unclaimed {
+		query = query.Where("claims.id IS NULL")
+	}
+	if filter.ClaimedBy != 0 {
+		query = query.Where("claims.moderator_id = ?", filter.ClaimedBy)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := []models.QueueItem{}
+	if err := query.
+		Select("queue.kind, queue.id, queue.user_id, queue.project_title, queue.created_at, " +
+			"claims.moderator_id AS claimed_by, claims.moderator_username AS claimed_by_username, claims.expires_at AS claim_expires_at").
+		Order("queue.created_at ASC, queue.kind ASC, queue.id ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for i := range items {
+		items[i].AgeSeconds = int64(now.Sub(items[i].CreatedAt).Seconds())
+	}
+	return items, total, nil
+}
+
+// Claim locks a pending prompt to the actor for the configured claim TTL. Claiming
+// an item the actor already holds extends the claim; expired claims can be taken over.
+func Claim(actor Actor, kindName string, id uint) (*models.ModerationClaim, error) {
+	kind, ok := LookupKind(kindName)
+	if !ok {
+		return nil, ErrInvalidMediaType
+	}
+
+	var status string
+	if err := config.DB.Table(kind.Table()).Select("status").Where("id = ?", id).Row().Scan(&status); err != nil {
+		return nil, ErrNotFound
+	}
+	if status != models.PromptStatusPending {
+		return nil, ErrInvalidTransition
+	}
+
+	now := time.Now()
+	claim := models.ModerationClaim{
+		TargetType:        kindName,
+		TargetID:          id,
+		ModeratorID:       actor.UserID,
+		ModeratorUsername: actor.Username,
+		ExpiresAt:         now.Add(config.AppConfig.ModerationClaimTTL),
+	}
+
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&claim)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return &claim, nil
+	}
+
+	// Someone holds a claim row: extend our own, or take over an expired one
+	result = config.DB.Model(&models.ModerationClaim{}).
+		Where("target_type = ? AND target_id = ? AND (moderator_id = ? OR expires_at <= ?)", kindName, id, actor.UserID, now).
+		Updates(map[string]interface{}{
+			"moderator_id":       claim.ModeratorID,
+			"moderator_username": claim.ModeratorUsername,
+			"expires_at":         claim.ExpiresAt,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrClaimed
+	}
+
+	if err := config.DB.Where("target_type = ? AND target_id = ?", kindName, id).First(&claim).Error; err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// ClaimNext claims up to n of the oldest unclaimed pending prompts
+func ClaimNext(actor Actor, kindName string, n int) ([]models.ModerationClaim, error) {
+	claims := []models.ModerationClaim{}
+
+	// Candidates can be taken by a concurrent moderator; over-fetch and skip those
+	items, _, err := ListQueue(QueueFilter{Kind: kindName, Unclaimed: true}, n*2, 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if len(claims) == n {
+			break
+		}
+		claim, err := Claim(actor, item.Kind, item.ID)
+		if errors.Is(err, ErrClaimed) || errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, *claim)
+	}
+	return claims, nil
+}
+
+// ReleaseClaim drops the actor's claim on a prompt; prompt managers may release anyone's
+func ReleaseClaim(actor Actor, kindName string, id uint) error {
+	query := config.DB.Where("target_type = ? AND target_id = ?", kindName, id)
+	if !models.HasPermission(actor.Role, models.PermPromptsManage) {
+		query = query.Where("moderator_id = ?", actor.UserID)
+	}
+
+	result := query.Delete(&models.ModerationClaim{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkClaim fails when another moderator holds an active claim on a prompt
+func checkClaim(tx *gorm.DB, actor Actor, kindName string, id uint) error {
+	var count int64
+	if err := tx.Model(&models.ModerationClaim{}).
+		Where("target_type = ? AND target_id = ? AND moderator_id <> ? AND expires_at > ?", kindName, id, actor.UserID, time.Now()).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrClaimed
+	}
+	return nil
+}
+
+// clearClaim removes any claim on a prompt once it has been decided
+func clearClaim(tx *gorm.DB, kindName string, id uint) error {
+	return tx.Where("target_type = ? AND target_id = ?", kindName, id).Delete(&models.ModerationClaim{}).Error
+}
+
+// GetQueueStats summarises the queue and counts each moderator's decisions since window ago
+func GetQueueStats(window time.Duration) (*models.QueueStats, error) {
+	now := time.Now()
+	stats := &models.QueueStats{
+		SizeByKind:  map[string]int64{},
+		WindowHours: window.Hours(),
+		Throughput:  []models.ModeratorThroughput{},
+	}
+
+	var sizes []struct {
+		Kind   string
+		Count  int64
+		Oldest *time.Time
+	}
+	if err := config.DB.Table("(?) AS queue", pendingUnion()).
+		Select("kind, COUNT(*) AS count, MIN(created_at) AS oldest").
+		Group("kind").
+		Scan(&sizes).Error; err != nil {
+		return nil, err
+	}
+	var oldest *time.Time
+	for _, kind := range Kinds {
+		stats.SizeByKind[kind.MediaKind().Name] = 0
+	}
+	for _, size := range sizes {
+		stats.SizeByKind[size.Kind] = size.Count
+		stats.Size += size.Count
+		if size.Oldest != nil && (oldest == nil || size.Oldest.Before(*oldest)) {
+			oldest = size.Oldest
+		}
+	}
+	if oldest != nil {
+		stats.OldestAgeSeconds = int64(now.Sub(*oldest).Seconds())
+	}
+
+	if err := queueQuery(now).Where("claims.id IS NOT NULL").Count(&stats.Claimed).Error; err != nil {
+		return nil, err
+	}
+
+	if err := config.DB.Model(&models.ModerationEvent{}).
+		Select(fmt.Sprintf("actor_id AS moderator_id, MAX(actor_username) AS username, "+
+			"SUM(CASE WHEN action = '%s' THEN 1 ELSE 0 END) AS approved, "+
+			"SUM(CASE WHEN action = '%s' THEN 1 ELSE 0 END) AS rejected, "+
+			"COUNT(*) AS total", models.ModerationApprove, models.ModerationReject)).
+		Where("action IN ? AND created_at >= ?", []string{models.ModerationApprove, models.ModerationReject}, now.Add(-window)).
+		Group("actor_id").
+		Order("total DESC").
+		Scan(&stats.Throughput).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
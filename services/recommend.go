@@ -0,0 +1,346 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Recommendation tuning
+const (
+	interestWeight     = 1.0 // Weight of a tag the user picked as an interest
+	likedTagWeight     = 0.6 // Weight of the tag the user liked most; others scale down
+	affinityBlend      = 0.7 // Share of the score from tag affinity; the rest is weekly trending
+	candidatesPerKind  = 500 // Most recent tag-matched prompts considered per kind
+	trendingCandidates = 200 // Top weekly trending prompts always considered
+	recentCandidates   = 100 // Newest prompts used when nothing else is available
+	coldStartBlend     = 0.0 // Cold-start users are ranked by trending alone
+)
+
+// RecommendSortKeys are the orderings accepted by the for-you feed, which only
+// pages by page number
+var RecommendSortKeys = []utils.SortKey{
+	{Name: "score", Expr: "score"},
+}
+
+// recommendation is a candidate prompt and its score components
+type recommendation struct {
+	Ref      PromptRef
+	Affinity float64
+	Trending float64
+	Score    float64
+}
+
+// tagProfile weighs tags by the user's interests and the tags of prompts they liked
+func tagProfile(userID uint) (map[uint]float64, error) {
+	profile := map[uint]float64{}
+
+	var interests []uint
+	if err := config.DB.Table("user_interests").Where("user_id = ?", userID).Pluck("tag_id", &interests).Error; err != nil {
+		return nil, err
+	}
+	for _, tagID := range interests {
+		profile[tagID] += interestWeight
+	}
+
+	liked := map[uint]int{}
+	mostLiked := 0
+	for _, kind := range Kinds {
+		joinTable, column := kind.TagJoin()
+		var rows []struct {
+			TagID uint
+			Count int
+		}
+		if err := config.DB.Table("likes").
+			Select(joinTable+".tag_id AS tag_id, COUNT(*) AS count").
+			Joins("JOIN "+joinTable+" ON "+joinTable+"."+column+" = likes.target_id").
+			Where("likes.user_id = ? AND likes.target_type = ?", userID, kind.MediaKind().Name).
+			Group(joinTable + ".tag_id").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			liked[row.TagID] += row.Count
+			if liked[row.TagID] > mostLiked {
+				mostLiked = liked[row.TagID]
+			}
+		}
+	}
+	for tagID, count := range liked {
+		profile[tagID] += likedTagWeight * float64(count) / float64(mostLiked)
+	}
+
+	return profile, nil
+}
+
+// publishedPrompts starts a query over a kind's approved, published prompts
+func publishedPrompts(kind KindService) *gorm.DB {
+	return config.DB.Table(kind.Table()).Where("status = ? AND is_published = ?", models.PromptStatusApproved, true)
+}
+
+// publishedBy restricts publishedPrompts to those of other users
+func publishedBy(kind KindService, userID uint) *gorm.DB {
+	return publishedPrompts(kind).Where("user_id <> ?", userID)
+}
+
+// addTagCandidates scores the most recent prompts carrying the profile's tags
+func addTagCandidates(candidates map[PromptRef]*recommendation, profile map[uint]float64, userID uint) error {
+	tagIDs := make([]uint, 0, len(profile))
+	for tagID := range profile {
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	for _, kind := range Kinds {
+		joinTable, column := kind.TagJoin()
+
+		var ids []uint
+		if err := publishedBy(kind, userID).
+			Where("id IN (?)", config.DB.Table(joinTable).Select(column).Where("tag_id IN ?", tagIDs)).
+			Order("published_at DESC").
+			Limit(candidatesPerKind).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		var links []struct {
+			PromptID uint
+			TagID    uint
+		}
+		if err := config.DB.Table(joinTable).
+			Select(column+" AS prompt_id, tag_id").
+			Where(column+" IN ? AND tag_id IN ?", ids, tagIDs).
+			Scan(&links).Error; err != nil {
+			return err
+		}
+		for _, link := range links {
+			ref := PromptRef{Kind: kind.MediaKind().Name, ID: link.PromptID}
+			if candidates[ref] == nil {
+				candidates[ref] = &recommendation{Ref: ref}
+			}
+			candidates[ref].Affinity += profile[link.TagID]
+		}
+	}
+	return nil
+}
+
+// addTrendingCandidates adds the top weekly trending prompts of other users
+func addTrendingCandidates(candidates map[PromptRef]*recommendation, userID uint) error {
+	var scores []models.TrendingScore
+	if err := config.DB.Where("score_window = ? AND user_id <> ?", models.WindowWeekly, userID).
+		Order("score_rank").Limit(trendingCandidates).Find(&scores).Error; err != nil {
+		return err
+	}
+	for _, score := range scores {
+		ref := PromptRef{Kind: score.TargetType, ID: score.TargetID}
+		if candidates[ref] == nil {
+			candidates[ref] = &recommendation{Ref: ref}
+		}
+	}
+	return nil
+}
+
+// addRecentCandidates adds the newest prompts of other users, ranked by recency
+func addRecentCandidates(candidates map[PromptRef]*recommendation, userID uint) error {
+	for _, kind := range Kinds {
+		var rows []struct {
+			ID          uint
+			PublishedAt time.Time
+		}
+		if err := publishedBy(kind, userID).Select("id, published_at").
+			Order("published_at DESC").Limit(recentCandidates).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			ref := PromptRef{Kind: kind.MediaKind().Name, ID: row.ID}
+			// A tiny recency score orders the fallback newest first
+			candidates[ref] = &recommendation{Ref: ref, Score: float64(row.PublishedAt.Unix()) * 1e-12}
+		}
+	}
+	return nil
+}
+
+// scoreCandidates fills in weekly trending scores and drops prompts the user already
+// liked, along with trending entries for prompts no longer published, so they don't
+// skew the normalization
+func scoreCandidates(candidates map[PromptRef]*recommendation, userID uint) error {
+	idsByKind := map[string][]uint{}
+	for ref := range candidates {
+		idsByKind[ref.Kind] = append(idsByKind[ref.Kind], ref.ID)
+	}
+
+	for kindName, ids := range idsByKind {
+		kind, ok := LookupKind(kindName)
+		if !ok {
+			for _, id := range ids {
+				delete(candidates, PromptRef{Kind: kindName, ID: id})
+			}
+			continue
+		}
+		var published []uint
+		if err := publishedPrompts(kind).Where("id IN ?", ids).Pluck("id", &published).Error; err != nil {
+			return err
+		}
+		live := make(map[uint]bool, len(published))
+		for _, id := range published {
+			live[id] = true
+		}
+		for _, id := range ids {
+			if !live[id] {
+				delete(candidates, PromptRef{Kind: kindName, ID: id})
+			}
+		}
+
+		var liked []uint
+		if err := config.DB.Model(&models.Like{}).
+			Where("user_id = ? AND target_type = ? AND target_id IN ?", userID, kindName, ids).
+			Pluck("target_id", &liked).Error; err != nil {
+			return err
+		}
+		for _, id := range liked {
+			delete(candidates, PromptRef{Kind: kindName, ID: id})
+		}
+
+		var scores []models.TrendingScore
+		if err := config.DB.Where("score_window = ? AND target_type = ? AND target_id IN ?", models.WindowWeekly, kindName, ids).
+			Find(&scores).Error; err != nil {
+			return err
+		}
+		for _, score := range scores {
+			if c := candidates[PromptRef{Kind: kindName, ID: score.TargetID}]; c != nil {
+				c.Trending = score.Score
+			}
+		}
+	}
+	return nil
+}
+
+// Recommend ranks published prompts for a user by how well their tags match the
+// user's interests and like history, blended with weekly trending. Users with no
+// interests or likes get trending prompts, then the newest ones.
+func Recommend(viewer Viewer, page utils.PageRequest) ([]models.RecommendedPrompt, *utils.Pagination, error) {
+	profile, err := tagProfile(viewer.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := map[PromptRef]*recommendation{}
+	blend := coldStartBlend
+	if len(profile) > 0 {
+		blend = affinityBlend
+		if err := addTagCandidates(candidates, profile, viewer.UserID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := addTrendingCandidates(candidates, viewer.UserID); err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		if err := addRecentCandidates(candidates, viewer.UserID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := scoreCandidates(candidates, viewer.UserID); err != nil {
+		return nil, nil, err
+	}
+
+	// Normalize each component to [0, 1] before blending
+	var maxAffinity, maxTrending float64
+	for _, c := range candidates {
+		if c.Affinity > maxAffinity {
+			maxAffinity = c.Affinity
+		}
+		if c.Trending > maxTrending {
+			maxTrending = c.Trending
+		}
+	}
+	ranked := make([]*recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		if maxAffinity > 0 {
+			c.Score += blend * c.Affinity / maxAffinity
+		}
+		if maxTrending > 0 {
+			c.Score += (1 - blend) * c.Trending / maxTrending
+		}
+		ranked = append(ranked, c)
+	}
+	return rankedPage(viewer, ranked, page)
+}
+
+// rankedPage orders candidates by score and returns one page of them, loaded with
+// their user and tags. Candidates the viewer may not see, such as stale trending
+// entries for prompts since unpublished or deleted, are dropped before paging so
+// the totals match what can be shown.
+func rankedPage(viewer Viewer, ranked []*recommendation, page utils.PageRequest) ([]models.RecommendedPrompt, *utils.Pagination, error) {
+	refs := make([]PromptRef, len(ranked))
+	for i, c := range ranked {
+		refs[i] = c.Ref
+	}
+	visible, err := visibleRefs(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+	shown := ranked[:0]
+	for _, c := range ranked {
+		if visible[c.Ref] {
+			shown = append(shown, c)
+		}
+	}
+	ranked = shown
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		if ranked[i].Ref.Kind != ranked[j].Ref.Kind {
+			return ranked[i].Ref.Kind < ranked[j].Ref.Kind
+		}
+		return ranked[i].Ref.ID > ranked[j].Ref.ID
+	})
+
+	if page.Page == 0 {
+		page.Page = 1
+	}
+	pagination := &utils.Pagination{
+		Limit:      page.Limit,
+		Total:      int64(len(ranked)),
+		Page:       page.Page,
+		TotalPages: (len(ranked) + page.Limit - 1) / page.Limit,
+		Sort:       page.Sort.Name,
+		Order:      "desc",
+	}
+	start := (page.Page - 1) * page.Limit
+	if start > len(ranked) {
+		start = len(ranked)
+	}
+	end := start + page.Limit
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	ranked = ranked[start:end]
+
+	refs = make([]PromptRef, len(ranked))
+	for i, c := range ranked {
+		refs[i] = c.Ref
+	}
+	prompts, err := LoadPrompts(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]models.RecommendedPrompt, 0, len(ranked))
+	for i, c := range ranked {
+		if prompts[i] == nil {
+			continue
+		}
+		items = append(items, models.RecommendedPrompt{Kind: c.Ref.Kind, Score: c.Score, Prompt: prompts[i]})
+	}
+	return items, pagination, nil
+}
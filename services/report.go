@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Report errors
+var (
+	ErrAlreadyReported = errors.New("you have already reported this prompt")
+	ErrReportOwnPrompt = errors.New("you cannot report your own prompt")
+	ErrReportResolved  = errors.New("report is already resolved")
+)
+
+// SystemActor performs automatic actions such as report-triggered unpublishing
+var SystemActor = Actor{Username: "system", Role: models.RoleAdmin}
+
+// ReportFilter holds the filters accepted by the report triage list
+type ReportFilter struct {
+	Status   string
+	Kind     string
+	TargetID string
+	Reason   string
+}
+
+// ReportSortKeys are the orderings accepted by the report triage list
+var ReportSortKeys = []utils.SortKey{
+	{Name: "created_at", Expr: "created_at", Time: true},
+}
+
+// CreateReport flags a prompt the reporter can see. Once the prompt collects the
+// configured number of open reports it is unpublished pending review.
+func CreateReport(reporter Actor, kindName string, id uint, reason, details string) (*models.Report, error) {
+	kind, ok := LookupKind(kindName)
+	if !ok {
+		return nil, ErrInvalidMediaType
+	}
+
+	base, err := kind.FindBase(id)
+	if err != nil {
+		return nil, err
+	}
+	if !(Viewer{UserID: reporter.UserID}).CanView(base) {
+		return nil, ErrNotFound
+	}
+	if base.UserID == reporter.UserID {
+		return nil, ErrReportOwnPrompt
+	}
+
+	report := models.Report{
+		TargetType: kindName,
+		TargetID:   id,
+		ReporterID: reporter.UserID,
+		Reason:     reason,
+		Details:    details,
+		Status:     models.ReportStatusOpen,
+	}
+	result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&report)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrAlreadyReported
+	}
+
+	var open int64
+	if err := config.DB.Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", kindName, id, models.ReportStatusOpen).
+		Count(&open).Error; err != nil {
+		return nil, err
+	}
+	threshold := config.AppConfig.ReportThreshold
+	if threshold > 0 && open >= int64(threshold) && base.IsPublished {
+		if err := kind.Apply(SystemActor, id, BulkOperation{Action: BulkUnpublish}); err != nil {
+			log.Printf("⚠️  Failed to unpublish reported %s %d: %v", kindName, id, err)
+		}
+	}
+
+	return &report, nil
+}
+
+// ListReports returns one page of reports matching the filter
+func ListReports(filter ReportFilter, page utils.PageRequest) ([]models.Report, *utils.Pagination, error) {
+	query := config.DB.Model(&models.Report{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Kind != "" {
+		query = query.Where("target_type = ?", filter.Kind)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+
+	reports := []models.Report{}
+	pagination, err := utils.Paginate(query, page, &reports)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reports, pagination, nil
+}
+
+// reportActions maps triage actions to the moderation action they run. dismiss runs
+// none; approve only republishes, see restoreReported.
+var reportActions = map[string]string{
+	"dismiss":   "",
+	"approve":   "",
+	"reject":    BulkReject,
+	"publish":   BulkPublish,
+	"unpublish": BulkUnpublish,
+}
+
+// restoreReported republishes a reported prompt if the report threshold took it
+// offline. Prompts unpublished by staff stay unpublished.
+func restoreReported(actor Actor, kindName string, id uint) error {
+	kind, ok := LookupKind(kindName)
+	if !ok {
+		return ErrInvalidMediaType
+	}
+	base, err := kind.FindBase(id)
+	if err != nil {
+		return err
+	}
+	if base.IsPublished || base.Status != models.PromptStatusApproved {
+		return nil
+	}
+
+	var last models.AuditEvent
+	if err := config.DB.Where("target_type = ? AND target_id = ? AND action IN ?", kindName, id,
+		[]string{AuditPromptPublish, AuditPromptUnpublish}).
+		Order("id DESC").First(&last).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if last.Action != AuditPromptUnpublish || last.ActorID != SystemActor.UserID {
+		return nil
+	}
+	return kind.Apply(actor, id, BulkOperation{Action: BulkPublish})
+}
+
+// ResolveReport triages a report by running a moderation action on its prompt and
+// closing every open report on that prompt. Approving keeps the prompt as it is,
+// republishing it if the report threshold unpublished it, and dismisses the reports,
+// as does republishing; rejecting or unpublishing marks them actioned.
+func ResolveReport(actor Actor, reportID uint, action string, decision Decision) (*models.ReportResolution, error) {
+	bulkAction, ok := reportActions[action]
+	if !ok {
+		return nil, ErrUnknownAction
+	}
+
+	var report models.Report
+	if err := config.DB.First(&report, reportID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if report.Status != models.ReportStatusOpen {
+		return nil, ErrReportResolved
+	}
+
+	if action == "approve" {
+		if err := restoreReported(actor, report.TargetType, report.TargetID); err != nil {
+			return nil, err
+		}
+	}
+
+	if bulkAction != "" {
+		kind, ok := LookupKind(report.TargetType)
+		if !ok {
+			return nil, ErrInvalidMediaType
+		}
+		if bulkAction == BulkReject && !IsRejectionReason(decision.Reason) {
+			return nil, ErrInvalidReason
+		}
+		if err := kind.Apply(actor, report.TargetID, BulkOperation{Action: bulkAction, Decision: decision}); err != nil {
+			return nil, err
+		}
+	}
+
+	status := models.ReportStatusDismissed
+	if bulkAction == BulkReject || bulkAction == BulkUnpublish {
+		status = models.ReportStatusActioned
+	}
+
+	resolution := &models.ReportResolution{Action: action, Status: status}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		resolvedBy := actor.UserID
+		result := tx.Model(&models.Report{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", report.TargetType, report.TargetID, models.ReportStatusOpen).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"resolution":  action,
+				"resolved_by": resolvedBy,
+				"resolved_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		resolution.Resolved = result.RowsAffected
+
+		after := report
+		after.Status, after.Resolution, after.ResolvedBy = status, action, &resolvedBy
+		return RecordAudit(tx, actor, AuditReportResolve, AuditTargetReport, report.ID, report, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resolution, nil
+}
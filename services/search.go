@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrEmptySearch is returned when a search query has no searchable words
+var ErrEmptySearch = errors.New("search query has no searchable words")
+
+// searchMatch is the FULLTEXT match over the columns of idx_prompt_search
+const searchMatch = "MATCH(project_title, prompt, technical_notes, model_or_tool, creator_credit) AGAINST (? IN BOOLEAN MODE)"
+
+// facetLimit caps the tag and model facets to the most common values
+const facetLimit = 20
+
+// SearchFilter holds the filters accepted by Search
+type SearchFilter struct {
+	Query       string
+	Kind        string // Media kind name; empty searches every kind
+	TagIDs      []uint // Prompts must carry all of them
+	ModelOrTool string
+	From        *time.Time // Published at or after
+	To          *time.Time // Published before
+	IsFeatured  bool
+}
+
+// SearchSortKeys are the orderings accepted by search, which only pages by page number
+var SearchSortKeys = []utils.SortKey{
+	{Name: "relevance", Expr: "score"},
+	{Name: "published_at", Expr: "published_at", Time: true},
+	{Name: "likes_count", Expr: "likes_count"},
+}
+
+// searchRow is a matching prompt of any kind
+type searchRow struct {
+	Kind  string
+	ID    uint
+	Score float64
+}
+
+// booleanQuery turns free text into a FULLTEXT boolean query requiring every word,
+// matching word prefixes
+func booleanQuery(text string) string {
+	tokens := tokenize(text)
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = "+" + token + "*"
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchScope restricts a kind's published prompts to those matching the filter
+func searchScope(kind KindService, filter SearchFilter, match string) *gorm.DB {
+	query := publishedPrompts(kind)
+	if match != "" {
+		query = query.Where(searchMatch, match)
+	}
+	if len(filter.TagIDs) > 0 {
+		joinTable, column := kind.TagJoin()
+		query = query.Where("id IN (?)", config.DB.Table(joinTable).Select(column).
+			Where("tag_id IN ?", filter.TagIDs).
+			Group(column).
+			Having("COUNT(DISTINCT tag_id) = ?", len(filter.TagIDs)))
+	}
+	if filter.ModelOrTool != "" {
+		query = query.Where("model_or_tool = ?", filter.ModelOrTool)
+	}
+	if filter.From != nil {
+		query = query.Where("published_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("published_at < ?", *filter.To)
+	}
+	if filter.IsFeatured {
+		query = query.Where("is_featured = ?", true)
+	}
+	return query
+}
+
+// searchFacets counts the tags and models of every prompt matched by the scopes
+func searchFacets(scopes map[KindService]*gorm.DB) (models.SearchFacets, error) {
+	facets := models.SearchFacets{Tags: []models.TagFacet{}, Models: []models.ModelFacet{}}
+	tagCounts := map[uint]int64{}
+	modelCounts := map[string]int64{}
+
+	for kind, scope := range scopes {
+		joinTable, column := kind.TagJoin()
+		var tagRows []struct {
+			TagID uint
+			Count int64
+		}
+		if err := config.DB.Table(joinTable).
+			Select("tag_id, COUNT(*) AS count").
+			Where(column+" IN (?)", scope.Session(&gorm.Session{}).Select("id")).
+			Group("tag_id").
+			Scan(&tagRows).Error; err != nil {
+			return facets, err
+		}
+		for _, row := range tagRows {
+			tagCounts[row.TagID] += row.Count
+		}
+
+		var modelRows []struct {
+			ModelOrTool string
+			Count       int64
+		}
+		if err := scope.Session(&gorm.Session{}).
+			Select("model_or_tool, COUNT(*) AS count").
+			Where("model_or_tool <> ''").
+			Group("model_or_tool").
+			Scan(&modelRows).Error; err != nil {
+			return facets, err
+		}
+		for _, row := range modelRows {
+			modelCounts[row.ModelOrTool] += row.Count
+		}
+	}
+
+	if len(tagCounts) > 0 {
+		tagIDs := make([]uint, 0, len(tagCounts))
+		for tagID := range tagCounts {
+			tagIDs = append(tagIDs, tagID)
+		}
+		var tags []models.Tag
+		if err := config.DB.Where("id IN ? AND is_active = ?", tagIDs, true).Find(&tags).Error; err != nil {
+			return facets, err
+		}
+		for _, tag := range tags {
+			facets.Tags = append(facets.Tags, models.TagFacet{ID: tag.ID, Name: tag.Name, Count: tagCounts[tag.ID]})
+		}
+		sort.Slice(facets.Tags, func(i, j int) bool {
+			if facets.Tags[i].Count != facets.Tags[j].Count {
+				return facets.Tags[i].Count > facets.Tags[j].Count
+			}
+			return facets.Tags[i].Name < facets.Tags[j].Name
+		})
+		if len(facets.Tags) > facetLimit {
+			facets.Tags = facets.Tags[:facetLimit]
+		}
+	}
+
+	for model, count := range modelCounts {
+		facets.Models = append(facets.Models, models.ModelFacet{ModelOrTool: model, Count: count})
+	}
+	sort.Slice(facets.Models, func(i, j int) bool {
+		if facets.Models[i].Count != facets.Models[j].Count {
+			return facets.Models[i].Count > facets.Models[j].Count
+		}
+		return facets.Models[i].ModelOrTool < facets.Models[j].ModelOrTool
+	})
+	if len(facets.Models) > facetLimit {
+		facets.Models = facets.Models[:facetLimit]
+	}
+
+	return facets, nil
+}
+
+// Search pages through the published prompts of every kind whose title, prompt,
+// technical notes, model or credit match a query, with tag and model facets over
+// all matches. Without a query, matches are ordered by publication.
+func Search(viewer Viewer, filter SearchFilter, page utils.PageRequest) (*models.SearchResponse, *utils.Pagination, error) {
+	kinds := Kinds
+	if filter.Kind != "" {
+		kind, ok := LookupKind(filter.Kind)
+		if !ok {
+			return nil, nil, ErrInvalidMediaType
+		}
+		kinds = []KindService{kind}
+	}
+
+	var match string
+	if strings.TrimSpace(filter.Query) != "" {
+		if match = booleanQuery(filter.Query); match == "" {
+			return nil, nil, ErrEmptySearch
+		}
+	} else if page.Sort.Name == "relevance" {
+		page.Sort = SearchSortKeys[1]
+	}
+
+	if page.Page == 0 {
+		page.Page = 1
+	}
+	direction := "DESC"
+	if page.Asc {
+		direction = "ASC"
+	}
+	pagination := &utils.Pagination{
+		Limit: page.Limit,
+		Page:  page.Page,
+		Sort:  page.Sort.Name,
+		Order: strings.ToLower(direction),
+	}
+
+	scopes := map[KindService]*gorm.DB{}
+	parts := make([]string, 0, len(kinds))
+	args := make([]interface{}, 0, len(kinds))
+	for _, kind := range kinds {
+		scope := searchScope(kind, filter, match)
+		scopes[kind] = scope
+
+		score := "0"
+		scoreArgs := []interface{}{kind.MediaKind().Name}
+		if match != "" {
+			score = searchMatch
+			scoreArgs = append(scoreArgs, match)
+		}
+		parts = append(parts, "(?)")
+		args = append(args, scope.Session(&gorm.Session{}).
+			Select("? AS kind, id, published_at, likes_count, "+score+" AS score", scoreArgs...))
+	}
+	// Count and page the same union of published prompts, which every viewer may see,
+	// so the total matches the rows shown
+	union := "(" + strings.Join(parts, " UNION ALL ") + ") AS results"
+	if err := config.DB.Table(union, args...).Count(&pagination.Total).Error; err != nil {
+		return nil, nil, err
+	}
+	pagination.TotalPages = int((pagination.Total + int64(page.Limit) - 1) / int64(page.Limit))
+
+	var rows []searchRow
+	if err := config.DB.Table(union, args...).
+		Order(fmt.Sprintf("%s %s, kind %s, id %s", page.Sort.Expr, direction, direction, direction)).
+		Offset((page.Page - 1) * page.Limit).
+		Limit(page.Limit).
+		Scan(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]PromptRef, len(rows))
+	for i, row := range rows {
+		refs[i] = PromptRef{Kind: row.Kind, ID: row.ID}
+	}
+	prompts, err := LoadPrompts(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := &models.SearchResponse{Results: make([]models.SearchResult, 0, len(rows))}
+	for i, row := range rows {
+		if prompts[i] == nil {
+			continue
+		}
+		response.Results = append(response.Results, models.SearchResult{Kind: row.Kind, Score: row.Score, Prompt: prompts[i]})
+	}
+
+	if response.Facets, err = searchFacets(scopes); err != nil {
+		return nil, nil, err
+	}
+	return response, pagination, nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Session errors
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReuse   = errors.New("refresh token reuse detected")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+)
+
+// lastSeenResolution limits how often a session's last-seen time is written
+const lastSeenResolution = time.Minute
+
+// StartSession opens a new login session for a user and issues its first token pair;
+// mfaVerified records whether the login passed a second factor.
+func StartSession(user *models.User, client models.SessionClient, mfaVerified bool) (*models.AuthTokens, error) {
+	var tokens *models.AuthTokens
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		session := models.Session{
+			UserID:      user.ID,
+			Device:      utils.DescribeDevice(client.UserAgent),
+			IPAddress:   client.IPAddress,
+			UserAgent:   truncate(client.UserAgent, 500),
+			LastSeenAt:  now,
+			ExpiresAt:   now.Add(config.AppConfig.RefreshTokenTTL),
+			MFAVerified: mfaVerified,
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+
+		var err error
+		tokens, err = issueTokens(tx, user, &session)
+		return err
+	})
+	return tokens, err
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
+
+// issueTokens signs an access token and stores a fresh refresh token for a session
+func issueTokens(tx *gorm.DB, user *models.User, session *models.Session) (*models.AuthTokens, error) {
+	accessToken, expiresAt, err := utils.GenerateToken(utils.Claims{
+		UserID:       user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		Role:         user.Role,
+		SessionID:    session.ID,
+		TokenVersion: user.TokenVersion,
+		MFA:          session.MFAVerified,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(config.AppConfig.RefreshTokenTTL),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.AuthTokens{
+		Token:            accessToken,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+// RefreshSession exchanges a refresh token for a new token pair. Each refresh token
+// may be used once; presenting a used token revokes its whole session.
+func RefreshSession(rawToken string, client models.SessionClient) (*models.AuthTokens, *models.User, error) {
+	var (
+		tokens        *models.AuthTokens
+		user          models.User
+		reusedSession uint
+	)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var record models.RefreshToken
+		if err := tx.Where("token_hash = ?", utils.HashToken(rawToken)).First(&record).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+
+		var session models.Session
+		if err := tx.First(&session, record.SessionID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+		if session.RevokedAt != nil {
+			return ErrSessionRevoked
+		}
+
+		// Claim the token atomically so concurrent refreshes can't both succeed
+		now := time.Now()
+		result := tx.Model(&record).Where("used_at IS NULL").Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			reusedSession = session.ID
+			return ErrRefreshTokenReuse
+		}
+
+		if now.After(record.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		if err := tx.First(&user, session.UserID).Error; err != nil {
+			return ErrInvalidRefreshToken
+		}
+		if !user.IsActive {
+			return ErrSessionRevoked
+		}
+
+		var err error
+		tokens, err = issueTokens(tx, &user, &session)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&session).Updates(map[string]interface{}{
+			"ip_address":   client.IPAddress,
+			"user_agent":   truncate(client.UserAgent, 500),
+			"device":       utils.DescribeDevice(client.UserAgent),
+			"last_seen_at": now,
+			"expires_at":   tokens.RefreshExpiresAt,
+		}).Error
+	})
+
+	if reusedSession != 0 {
+		// A leaked token was replayed; kill the whole token family
+		if revokeErr := revokeSessions(config.DB.Where("id = ?", reusedSession)); revokeErr != nil {
+			return nil, nil, revokeErr
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user.PasswordHash = ""
+	return tokens, &user, nil
+}
+
+// revokeSessions marks the sessions matched by query as revoked
+func revokeSessions(query *gorm.DB) error {
+	return query.Model(&models.Session{}).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeSession revokes one of a user's sessions
+func RevokeSession(userID, sessionID uint) error {
+	return revokeSessions(config.DB.Where("id = ? AND user_id = ?", sessionID, userID))
+}
+
+// RevokeOtherSessions revokes every session of a user except the current one
+func RevokeOtherSessions(userID, currentSessionID uint) error {
+	return revokeSessions(config.DB.Where("user_id = ? AND id <> ?", userID, currentSessionID))
+}
+
+// ListActiveSessions returns a user's unrevoked, unexpired sessions, most recently
+// used first, flagging the current one
+func ListActiveSessions(userID, currentSessionID uint) ([]models.Session, error) {
+	sessions := []models.Session{}
+	if err := config.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeAllSessions signs a user out everywhere: every session is revoked and the
+// token version is bumped so outstanding access tokens stop validating
+func RevokeAllSessions(userID uint) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).
+			Update("token_version", gorm.Expr("token_version + 1")).Error; err != nil {
+			return err
+		}
+		return revokeSessions(tx.Where("user_id = ?", userID))
+	})
+}
+
+// RevokeSessionsTx revokes every session of a user inside tx, e.g. one being deleted
+func RevokeSessionsTx(tx *gorm.DB, userID uint) error {
+	return revokeSessions(tx.Where("user_id = ?", userID))
+}
+
+// CheckAccess verifies that an access token's user is still active, that the token
+// was issued for the user's current token version and that its session is live.
+// It also records session activity and refreshes the claimed role, so role changes
+// apply without signing the user out.
+func CheckAccess(claims *utils.Claims) error {
+	var user models.User
+	if err := config.DB.Select("id", "role", "is_active", "token_version").First(&user, claims.UserID).Error; err != nil {
+		return ErrSessionRevoked
+	}
+	if !user.IsActive || user.TokenVersion != claims.TokenVersion {
+		return ErrSessionRevoked
+	}
+	claims.Role = user.Role
+
+	var session models.Session
+	if err := config.DB.Select("id", "user_id", "revoked_at", "last_seen_at").First(&session, claims.SessionID).Error; err != nil {
+		return ErrSessionRevoked
+	}
+	if session.RevokedAt != nil || session.UserID != claims.UserID {
+		return ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) > lastSeenResolution {
+		config.DB.Model(&session).Update("last_seen_at", now)
+	}
+	return nil
+}
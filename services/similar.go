@@ -0,0 +1,238 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Similarity tuning
+const (
+	similarTagWeight   = 0.5 // Jaccard overlap of the two prompts' tags
+	similarModelWeight = 0.2 // Same model or tool
+	similarTextWeight  = 0.3 // TF-IDF cosine similarity of the prompt text
+	similarPoolPerKind = 300 // Prompts of each kind drawn per signal
+)
+
+// SimilarSortKeys are the orderings accepted by similar-prompt listings
+var SimilarSortKeys = []utils.SortKey{
+	{Name: "score", Expr: "score"},
+}
+
+// stopWords are left out of prompt text before comparing it
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"the": true, "to": true, "with": true, "its": true, "this": true, "that": true, "into": true,
+}
+
+// tokenize lowercases text and splits it into words, dropping stop words and single characters
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !stopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// tfidf weighs each document's terms by frequency and rarity across the documents
+func tfidf(docs [][]string) []map[string]float64 {
+	df := map[string]int{}
+	for _, doc := range docs {
+		seen := map[string]bool{}
+		for _, term := range doc {
+			if !seen[term] {
+				seen[term] = true
+				df[term]++
+			}
+		}
+	}
+
+	n := float64(len(docs))
+	vectors := make([]map[string]float64, len(docs))
+	for i, doc := range docs {
+		vector := map[string]float64{}
+		for _, term := range doc {
+			vector[term]++
+		}
+		for term, tf := range vector {
+			vector[term] = tf / float64(len(doc)) * (math.Log((n+1)/float64(df[term]+1)) + 1)
+		}
+		vectors[i] = vector
+	}
+	return vectors
+}
+
+// cosine is the cosine similarity of two sparse vectors
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, wa := range a {
+		normA += wa * wa
+		dot += wa * b[term]
+	}
+	for _, wb := range b {
+		normB += wb * wb
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// jaccard is the overlap of two tag sets
+func jaccard(a, b map[uint]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for tagID := range a {
+		if b[tagID] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// promptTags returns the tag set of each of a kind's prompts
+func promptTags(kind KindService, ids []uint) (map[uint]map[uint]bool, error) {
+	joinTable, column := kind.TagJoin()
+	var links []struct {
+		PromptID uint
+		TagID    uint
+	}
+	if err := config.DB.Table(joinTable).
+		Select(column+" AS prompt_id, tag_id").
+		Where(column+" IN ?", ids).
+		Scan(&links).Error; err != nil {
+		return nil, err
+	}
+
+	tags := map[uint]map[uint]bool{}
+	for _, link := range links {
+		if tags[link.PromptID] == nil {
+			tags[link.PromptID] = map[uint]bool{}
+		}
+		tags[link.PromptID][link.TagID] = true
+	}
+	return tags, nil
+}
+
+// similarCandidate is a published prompt compared against the source prompt
+type similarCandidate struct {
+	Ref         PromptRef
+	Prompt      string
+	ModelOrTool string
+	Tags        map[uint]bool
+}
+
+// similarPool gathers published prompts of every kind that share a tag or the
+// model with the source, plus the newest ones for text-only matches
+func similarPool(source PromptRef, sourceTags map[uint]bool, modelOrTool string) ([]*similarCandidate, error) {
+	tagIDs := make([]uint, 0, len(sourceTags))
+	for tagID := range sourceTags {
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	var pool []*similarCandidate
+	seen := map[PromptRef]bool{source: true}
+	for _, kind := range Kinds {
+		joinTable, column := kind.TagJoin()
+		queries := []*gorm.DB{publishedPrompts(kind)}
+		if len(tagIDs) > 0 {
+			queries = append(queries, publishedPrompts(kind).
+				Where("id IN (?)", config.DB.Table(joinTable).Select(column).Where("tag_id IN ?", tagIDs)))
+		}
+		if modelOrTool != "" {
+			queries = append(queries, publishedPrompts(kind).Where("model_or_tool = ?", modelOrTool))
+		}
+
+		var ids []uint
+		for _, query := range queries {
+			var rows []struct {
+				ID          uint
+				Prompt      string
+				ModelOrTool string
+			}
+			if err := query.Select("id, prompt, model_or_tool").
+				Order("published_at DESC").Limit(similarPoolPerKind).
+				Scan(&rows).Error; err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				ref := PromptRef{Kind: kind.MediaKind().Name, ID: row.ID}
+				if seen[ref] {
+					continue
+				}
+				seen[ref] = true
+				ids = append(ids, row.ID)
+				pool = append(pool, &similarCandidate{Ref: ref, Prompt: row.Prompt, ModelOrTool: row.ModelOrTool})
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		tags, err := promptTags(kind, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range pool {
+			if candidate.Ref.Kind == kind.MediaKind().Name {
+				candidate.Tags = tags[candidate.Ref.ID]
+			}
+		}
+	}
+	return pool, nil
+}
+
+// Similar ranks published prompts of every media kind by how alike they are to a
+// prompt: shared tags, the same model or tool, and similar prompt text
+func Similar(viewer Viewer, kindName string, id uint, page utils.PageRequest) ([]models.RecommendedPrompt, *utils.Pagination, error) {
+	kind, base, err := visiblePrompt(viewer, kindName, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceTags, err := promptTags(kind, []uint{id})
+	if err != nil {
+		return nil, nil, err
+	}
+	modelOrTool := strings.TrimSpace(base.ModelOrTool)
+	pool, err := similarPool(PromptRef{Kind: kindName, ID: id}, sourceTags[id], modelOrTool)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The source document comes last so term rarity is measured across the pool
+	docs := make([][]string, 0, len(pool)+1)
+	for _, candidate := range pool {
+		docs = append(docs, tokenize(candidate.Prompt))
+	}
+	docs = append(docs, tokenize(base.Prompt))
+	vectors := tfidf(docs)
+	sourceVector := vectors[len(pool)]
+
+	ranked := make([]*recommendation, 0, len(pool))
+	for i, candidate := range pool {
+		score := similarTagWeight*jaccard(sourceTags[id], candidate.Tags) +
+			similarTextWeight*cosine(sourceVector, vectors[i])
+		if modelOrTool != "" && strings.EqualFold(strings.TrimSpace(candidate.ModelOrTool), modelOrTool) {
+			score += similarModelWeight
+		}
+		if score > 0 {
+			ranked = append(ranked, &recommendation{Ref: candidate.Ref, Score: score})
+		}
+	}
+	return rankedPage(viewer, ranked, page)
+}
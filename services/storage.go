@@ -0,0 +1,56 @@
+package services
+
+import (
+	"mime/multipart"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/utils"
+)
+
+// MediaStorage stores the binary asset behind a prompt
+type MediaStorage interface {
+	// Upload stores the file and returns its canonical URL
+	Upload(file multipart.File, header *multipart.FileHeader) (string, error)
+	// Delete removes the asset behind a canonical URL
+	Delete(url string) error
+	// SignURL returns a URL clients can use to fetch the asset
+	SignURL(url string) (string, error)
+}
+
+// CloudinaryStorage keeps assets in Cloudinary with public URLs
+type CloudinaryStorage struct{}
+
+func (CloudinaryStorage) Upload(file multipart.File, header *multipart.FileHeader) (string, error) {
+	return utils.UploadImage(file, header.Filename)
+}
+
+func (CloudinaryStorage) Delete(url string) error {
+	publicID := utils.GetPublicIDFromURL(url)
+	if publicID == "" {
+		return nil
+	}
+	return utils.DeleteImage(publicID)
+}
+
+func (CloudinaryStorage) SignURL(url string) (string, error) {
+	return url, nil
+}
+
+// B2Storage keeps assets in a private Backblaze B2 bucket served through signed URLs
+type B2Storage struct {
+	Folder string
+	// Bucket resolves the bucket name from the loaded configuration
+	Bucket func(cfg *config.Config) string
+}
+
+func (s B2Storage) Upload(file multipart.File, header *multipart.FileHeader) (string, error) {
+	return utils.UploadToB2(file, header, s.Folder, s.Bucket(config.AppConfig))
+}
+
+func (s B2Storage) Delete(url string) error {
+	return utils.DeleteFromB2(url, s.Bucket(config.AppConfig))
+}
+
+func (s B2Storage) SignURL(url string) (string, error) {
+	return utils.GetSignedURL(url, s.Bucket(config.AppConfig))
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LockoutPolicy describes when repeated failures lock a throttle key and for how long
+type LockoutPolicy struct {
+	MaxFailures int           // Failures before the first lockout
+	Window      time.Duration // Failures older than this are forgotten
+	BaseLockout time.Duration // First lockout; doubles with every further failure
+	MaxLockout  time.Duration
+}
+
+// LoginAccountPolicy throttles failed logins per account
+func LoginAccountPolicy() LockoutPolicy {
+	cfg := config.AppConfig
+	return LockoutPolicy{
+		MaxFailures: cfg.LoginMaxFailuresPerAccount,
+		Window:      cfg.LoginFailureWindow,
+		BaseLockout: cfg.LoginLockoutBase,
+		MaxLockout:  cfg.LoginLockoutMax,
+	}
+}
+
+// LoginIPPolicy throttles failed logins per client IP
+func LoginIPPolicy() LockoutPolicy {
+	policy := LoginAccountPolicy()
+	policy.MaxFailures = config.AppConfig.LoginMaxFailuresPerIP
+	return policy
+}
+
+// LoginAccountKey is the throttle key for an account's login failures
+func LoginAccountKey(email string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// LoginIPKey is the throttle key for an IP address's login failures
+func LoginIPKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// lockout returns how long a key with the given failure count stays locked
+func (p LockoutPolicy) lockout(failures int) time.Duration {
+	if failures < p.MaxFailures {
+		return 0
+	}
+	d := p.BaseLockout
+	for i := p.MaxFailures; i < failures && d < p.MaxLockout; i++ {
+		d *= 2
+	}
+	if d > p.MaxLockout {
+		d = p.MaxLockout
+	}
+	return d
+}
+
+// LockedFor returns the longest remaining lockout among the keys, or zero
+func LockedFor(keys ...string) (time.Duration, error) {
+	var throttles []models.AuthThrottle
+	now := time.Now()
+	if err := config.DB.Where("throttle_key IN ? AND locked_until > ?", keys, now).Find(&throttles).Error; err != nil {
+		return 0, err
+	}
+
+	var longest time.Duration
+	for _, t := range throttles {
+		if remaining := t.LockedUntil.Sub(now); remaining > longest {
+			longest = remaining
+		}
+	}
+	return longest, nil
+}
+
+// RecordFailure counts a failed attempt against a key and locks it once the
+// policy's threshold is reached
+func RecordFailure(key string, policy LockoutPolicy) error {
+	now := time.Now()
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.AuthThrottle{ThrottleKey: key, LastFailureAt: now}).Error; err != nil {
+			return err
+		}
+
+		var throttle models.AuthThrottle
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("throttle_key = ?", key).First(&throttle).Error; err != nil {
+			return err
+		}
+
+		if now.Sub(throttle.LastFailureAt) > policy.Window {
+			throttle.Failures = 0
+		}
+		throttle.Failures++
+		throttle.LastFailureAt = now
+		if d := policy.lockout(throttle.Failures); d > 0 {
+			until := now.Add(d)
+			throttle.LockedUntil = &until
+		}
+
+		if err := tx.Save(&throttle).Error; err != nil {
+			return fmt.Errorf("failed to record failure for %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// ResetFailures clears a key after a successful attempt
+func ResetFailures(key string) error {
+	return config.DB.Where("throttle_key = ?", key).Delete(&models.AuthThrottle{}).Error
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"ai-of-the-world-backend/config"
+	"ai-of-the-world-backend/models"
+	"ai-of-the-world-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// Engagement weights
+const (
+	likeWeight     = 3
+	downloadWeight = 2
+	viewWeight     = 1
+)
+
+// TrendingSortKeys are the orderings accepted by the trending and leaderboard listings
+var TrendingSortKeys = []utils.SortKey{
+	{Name: "rank", Expr: "score_rank", Asc: true},
+}
+
+// scoreWindow describes how far back a window looks and how fast engagement decays in it
+type scoreWindow struct {
+	Name     string
+	Span     time.Duration // Zero for all time
+	HalfLife time.Duration // Zero for no decay
+}
+
+func scoreWindows() []scoreWindow {
+	cfg := config.AppConfig
+	return []scoreWindow{
+		{Name: models.WindowDaily, Span: 24 * time.Hour, HalfLife: cfg.TrendingHalfLifeDaily},
+		{Name: models.WindowWeekly, Span: 7 * 24 * time.Hour, HalfLife: cfg.TrendingHalfLifeWeekly},
+		{Name: models.WindowAllTime},
+	}
+}
+
+// decay weighs engagement of the given age: it counts half after every half-life
+func (w scoreWindow) decay(age time.Duration) float64 {
+	if w.HalfLife <= 0 {
+		return 1
+	}
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, age.Hours()/w.HalfLife.Hours())
+}
+
+// rankedPrompt is an eligible prompt with its lifetime counters
+type rankedPrompt struct {
+	ID             uint
+	UserID         uint
+	LikesCount     int
+	ViewsCount     int
+	DownloadsCount int
+}
+
+// eligiblePrompts loads the approved, published prompts of active creators
+func eligiblePrompts() (map[PromptRef]rankedPrompt, error) {
+	activeUsers := config.DB.Model(&models.User{}).Select("id").Where("is_active = ?", true)
+
+	eligible := map[PromptRef]rankedPrompt{}
+	for _, kind := range Kinds {
+		var rows []rankedPrompt
+		if err := config.DB.Table(kind.Table()).
+			Select("id, user_id, likes_count, views_count, downloads_count").
+			Where("status = ? AND is_published = ? AND user_id IN (?)", models.PromptStatusApproved, true, activeUsers).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			eligible[PromptRef{Kind: kind.MediaKind().Name, ID: row.ID}] = row
+		}
+	}
+	return eligible, nil
+}
+
+// windowScores scores the eligible prompts over a window. All-time scores use the
+// lifetime counters; shorter windows weigh each like and each day's views and
+// downloads by their age.
+func windowScores(window scoreWindow, eligible map[PromptRef]rankedPrompt, now time.Time) (map[PromptRef]float64, error) {
+	scores := map[PromptRef]float64{}
+
+	if window.Span == 0 {
+		for ref, p := range eligible {
+			scores[ref] = float64(p.LikesCount*likeWeight + p.DownloadsCount*downloadWeight + p.ViewsCount*viewWeight)
+		}
+		return scores, nil
+	}
+
+	since := now.Add(-window.Span)
+
+	// Likes, bucketed by hour of age
+	var likes []struct {
+		TargetType string
+		TargetID   uint
+		AgeHours   int
+		Count      int
+	}
+	if err := config.DB.Model(&models.Like{}).
+		Select("target_type, target_id, TIMESTAMPDIFF(HOUR, created_at, ?) AS age_hours, COUNT(*) AS count", now).
+		Where("created_at >= ?", since).
+		Group("target_type, target_id, age_hours").
+		Scan(&likes).Error; err != nil {
+		return nil, err
+	}
+	for _, l := range likes {
+		ref := PromptRef{Kind: l.TargetType, ID: l.TargetID}
+		if _, ok := eligible[ref]; ok {
+			scores[ref] += float64(l.Count*likeWeight) * window.decay(time.Duration(l.AgeHours)*time.Hour)
+		}
+	}
+
+	// Views and downloads, bucketed by day and aged from midday
+	var days []models.EngagementDay
+	if err := config.DB.Where("day >= ?", since.UTC().Truncate(24*time.Hour)).Find(&days).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range days {
+		ref := PromptRef{Kind: d.TargetType, ID: d.TargetID}
+		if _, ok := eligible[ref]; ok {
+			age := now.Sub(d.Day.Add(12 * time.Hour))
+			scores[ref] += float64(d.Downloads*downloadWeight+d.Views*viewWeight) * window.decay(age)
+		}
+	}
+
+	return scores, nil
+}
+
+// ComputeTrending recomputes every window's prompt and creator scores, then sets each
+// creator's TrendingScore (weekly score) and CommunityRank (all-time rank)
+func ComputeTrending(now time.Time) error {
+	eligible, err := eligiblePrompts()
+	if err != nil {
+		return err
+	}
+
+	var promptRows []models.TrendingScore
+	var creatorRows []models.CreatorScore
+	for _, window := range scoreWindows() {
+		scores, err := windowScores(window, eligible, now)
+		if err != nil {
+			return err
+		}
+
+		refs := make([]PromptRef, 0, len(scores))
+		creatorScores := map[uint]float64{}
+		for ref, score := range scores {
+			if score <= 0 {
+				continue
+			}
+			refs = append(refs, ref)
+			creatorScores[eligible[ref].UserID] += score
+		}
+		sort.Slice(refs, func(i, j int) bool {
+			if scores[refs[i]] != scores[refs[j]] {
+				return scores[refs[i]] > scores[refs[j]]
+			}
+			if refs[i].Kind != refs[j].Kind {
+				return refs[i].Kind < refs[j].Kind
+			}
+			return refs[i].ID < refs[j].ID
+		})
+		for i, ref := range refs {
+			promptRows = append(promptRows, models.TrendingScore{
+				Window:     window.Name,
+				TargetType: ref.Kind,
+				TargetID:   ref.ID,
+				UserID:     eligible[ref].UserID,
+				Score:      scores[ref],
+				Rank:       i + 1,
+				ComputedAt: now,
+			})
+		}
+
+		creators := make([]uint, 0, len(creatorScores))
+		for userID := range creatorScores {
+			creators = append(creators, userID)
+		}
+		sort.Slice(creators, func(i, j int) bool {
+			if creatorScores[creators[i]] != creatorScores[creators[j]] {
+				return creatorScores[creators[i]] > creatorScores[creators[j]]
+			}
+			return creators[i] < creators[j]
+		})
+		for i, userID := range creators {
+			creatorRows = append(creatorRows, models.CreatorScore{
+				Window:     window.Name,
+				UserID:     userID,
+				Score:      creatorScores[userID],
+				Rank:       i + 1,
+				ComputedAt: now,
+			})
+		}
+	}
+
+	// Swap in the new scores at once so readers never see a half-written ranking
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.TrendingScore{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&models.CreatorScore{}).Error; err != nil {
+			return err
+		}
+		if len(promptRows) > 0 {
+			if err := tx.CreateInBatches(promptRows, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(creatorRows) > 0 {
+			if err := tx.CreateInBatches(creatorRows, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Exec(`UPDATE users
+			LEFT JOIN creator_scores weekly ON weekly.user_id = users.id AND weekly.score_window = ?
+			LEFT JOIN creator_scores overall ON overall.user_id = users.id AND overall.score_window = ?
+			SET users.trending_score = COALESCE(ROUND(weekly.score), 0), users.community_rank = overall.score_rank`,
+			models.WindowWeekly, models.WindowAllTime).Error
+	})
+}
+
+// StartTrendingJob recomputes trending scores now and then every interval until stop is closed
+func StartTrendingJob(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ComputeTrending(time.Now()); err != nil {
+			log.Println("⚠️  Failed to compute trending scores:", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// ListTrending returns one page of a window's top prompts, optionally of one kind.
+// Prompts hidden since the last computation are left out.
+func ListTrending(viewer Viewer, window, kind string, page utils.PageRequest) ([]models.TrendingPrompt, *utils.Pagination, error) {
+	query := config.DB.Model(&models.TrendingScore{}).Where("score_window = ?", window)
+	if kind != "" {
+		query = query.Where("target_type = ?", kind)
+	}
+
+	scores := []models.TrendingScore{}
+	pagination, err := utils.Paginate(query, page, &scores)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := make([]PromptRef, len(scores))
+	for i, score := range scores {
+		refs[i] = PromptRef{Kind: score.TargetType, ID: score.TargetID}
+	}
+	prompts, err := LoadPrompts(viewer, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]models.TrendingPrompt, 0, len(scores))
+	for i, score := range scores {
+		if prompts[i] == nil {
+			continue
+		}
+		items = append(items, models.TrendingPrompt{Rank: score.Rank, Score: score.Score, Kind: score.TargetType, Prompt: prompts[i]})
+	}
+	return items, pagination, nil
+}
+
+// Leaderboard returns one page of a window's top creators
+func Leaderboard(window string, page utils.PageRequest) ([]models.LeaderboardEntry, *utils.Pagination, error) {
+	scores := []models.CreatorScore{}
+	pagination, err := utils.Paginate(config.DB.Model(&models.CreatorScore{}).Where("score_window = ?", window), page, &scores)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userIDs := make([]uint, len(scores))
+	for i, score := range scores {
+		userIDs[i] = score.UserID
+	}
+	var users []models.User
+	if len(userIDs) > 0 {
+		if err := config.DB.Where("id IN ? AND is_active = ?", userIDs, true).Find(&users).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	byID := make(map[uint]models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	entries := make([]models.LeaderboardEntry, 0, len(scores))
+	for _, score := range scores {
+		user, ok := byID[score.UserID]
+		if !ok {
+			continue
+		}
+		entries = append(entries, models.LeaderboardEntry{
+			Rank:              score.Rank,
+			Score:             score.Score,
+			UserID:            user.ID,
+			Username:          user.Username,
+			FullName:          user.FullName,
+			ProfilePictureURL: user.ProfilePictureURL,
+			IsVerified:        user.IsVerified,
+			TotalCreations:    user.TotalCreations,
+			TotalLikes:        user.TotalLikes,
+		})
+	}
+	return entries, pagination, nil
+}
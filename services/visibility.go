@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strconv"
+
+	"ai-of-the-world-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Viewer identifies who is reading prompts so the visibility policy can be applied
+type Viewer struct {
+	UserID       uint   // Zero for anonymous callers
+	Unrestricted bool   // Set on admin routes, which see every prompt
+	ClientIP     string // Identifies anonymous viewers when deduping views
+}
+
+// key identifies the viewer when deduping views and downloads
+func (v Viewer) key() string {
+	if v.UserID != 0 {
+		return "user:" + strconv.FormatUint(uint64(v.UserID), 10)
+	}
+	return "ip:" + v.ClientIP
+}
+
+// Scope restricts a prompt query to the rows the viewer may read: approved and
+// published prompts for everyone, plus the viewer's own submissions in any state
+func (v Viewer) Scope(query *gorm.DB) *gorm.DB {
+	if v.Unrestricted {
+		return query
+	}
+	if v.UserID == 0 {
+		return query.Where("status = ? AND is_published = ?", models.PromptStatusApproved, true)
+	}
+	return query.Where("((status = ? AND is_published = ?) OR user_id = ?)", models.PromptStatusApproved, true, v.UserID)
+}
+
+// CanView reports whether the viewer may read a single prompt
+func (v Viewer) CanView(prompt *models.PromptBase) bool {
+	if v.Unrestricted {
+		return true
+	}
+	if prompt.Status == models.PromptStatusApproved && prompt.IsPublished {
+		return true
+	}
+	return v.UserID != 0 && prompt.UserID == v.UserID
+}
@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 
 	"ai-of-the-world-backend/config"
@@ -8,44 +12,104 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token audiences keep MFA challenge tokens from being accepted as access tokens
+const (
+	audienceAccess = "access"
+	audienceMFA    = "mfa"
+)
+
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	SessionID    uint   `json:"sid"`
+	TokenVersion int    `json:"tv"`
+	MFA          bool   `json:"mfa,omitempty"` // Session was established with a second factor
+	jwt.RegisteredClaims
+}
+
+// GenerateToken generates a short-lived JWT access token for a user session
+func GenerateToken(claims Claims) (string, time.Time, error) {
+	expiresAt := time.Now().Add(config.AppConfig.AccessTokenTTL)
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{audienceAccess},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(config.AppConfig.JWTSecret))
+	return signed, expiresAt, err
+}
+
+// ValidateToken validates a JWT access token and returns the claims
+func ValidateToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := parseToken(tokenString, claims, audienceAccess); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// MFAClaims identifies a user who passed the password step of login
+type MFAClaims struct {
+	UserID uint `json:"user_id"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken generates a JWT token for a user
-func GenerateToken(userID uint, username, email, role string) (string, error) {
-	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
-		Role:     role,
+// GenerateMFAToken generates a short-lived challenge token for the second login step
+func GenerateMFAToken(userID uint, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := MFAClaims{
+		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			Audience:  jwt.ClaimStrings{audienceMFA},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(config.AppConfig.JWTSecret))
+	signed, err := token.SignedString([]byte(config.AppConfig.JWTSecret))
+	return signed, expiresAt, err
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+// ValidateMFAToken validates an MFA challenge token and returns the claims
+func ValidateMFAToken(tokenString string) (*MFAClaims, error) {
+	claims := &MFAClaims{}
+	if err := parseToken(tokenString, claims, audienceMFA); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func parseToken(tokenString string, claims jwt.Claims, audience string) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(config.AppConfig.JWTSecret), nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(audience))
 
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if !token.Valid {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
+// GenerateOpaqueToken returns a random URL-safe token and its SHA-256 hash for storage
+func GenerateOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
 	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
 
-	return nil, jwt.ErrSignatureInvalid
+// HashToken returns the hex SHA-256 hash of an opaque token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
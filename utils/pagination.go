@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidPageRequest = errors.New("invalid pagination parameters")
+
+// SortKey is a whitelisted ordering a list endpoint accepts via ?sort=
+type SortKey struct {
+	Name string // Value accepted in ?sort=
+	Expr string // SQL column or expression to order by
+	Time bool   // Whether Expr yields a timestamp
+	Asc  bool   // Default direction
+}
+
+// PageRequest holds the pagination parameters of a list request
+type PageRequest struct {
+	Limit  int
+	Page   int // Page number for offset pagination; 0 selects cursor pagination
+	Cursor string
+	Sort   SortKey
+	Asc    bool
+}
+
+// Pagination is the envelope returned alongside paginated data
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	Sort       string `json:"sort"`
+	Order      string `json:"order"`
+}
+
+// cursor is the decoded form of an opaque next_cursor value
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+// ParsePageRequest reads limit, page, cursor, sort and order from the query string.
+// The first sort key is the default.
+func ParsePageRequest(c *gin.Context, sortKeys []SortKey) (PageRequest, error) {
+	req := PageRequest{Limit: DefaultPageLimit, Sort: sortKeys[0]}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return req, fmt.Errorf("%w: limit must be a positive integer", ErrInvalidPageRequest)
+		}
+		if n > MaxPageLimit {
+			n = MaxPageLimit
+		}
+		req.Limit = n
+	}
+
+	if page := c.Query("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return req, fmt.Errorf("%w: page must be a positive integer", ErrInvalidPageRequest)
+		}
+		req.Page = n
+	}
+
+	req.Cursor = c.Query("cursor")
+	if req.Cursor != "" && req.Page > 0 {
+		return req, fmt.Errorf("%w: use either cursor or page, not both", ErrInvalidPageRequest)
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		found := false
+		names := make([]string, len(sortKeys))
+		for i, key := range sortKeys {
+			names[i] = key.Name
+			if key.Name == sort {
+				req.Sort = key
+				found = true
+			}
+		}
+		if !found {
+			return req, fmt.Errorf("%w: sort must be one of %s", ErrInvalidPageRequest, strings.Join(names, ", "))
+		}
+	}
+
+	req.Asc = req.Sort.Asc
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		req.Asc = true
+	case "desc":
+		req.Asc = false
+	default:
+		return req, fmt.Errorf("%w: order must be asc or desc", ErrInvalidPageRequest)
+	}
+
+	return req, nil
+}
+
+func (req PageRequest) direction() string {
+	if req.Asc {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// Paginate counts the rows matched by query and loads one page of them into dest,
+// which must be a pointer to a slice of models with an ID field. Preloads are applied
+// to the page query only.
+func Paginate(query *gorm.DB, req PageRequest, dest interface{}, preloads ...string) (*Pagination, error) {
+	query = query.Session(&gorm.Session{})
+
+	pagination := &Pagination{
+		Limit: req.Limit,
+		Sort:  req.Sort.Name,
+		Order: strings.ToLower(req.direction()),
+	}
+	if err := query.Count(&pagination.Total).Error; err != nil {
+		return nil, err
+	}
+
+	page := query
+	for _, preload := range preloads {
+		page = page.Preload(preload)
+	}
+	page = page.Order(fmt.Sprintf("%s %s, id %s", req.Sort.Expr, req.direction(), req.direction()))
+
+	if req.Page > 0 {
+		pagination.Page = req.Page
+		pagination.TotalPages = int((pagination.Total + int64(req.Limit) - 1) / int64(req.Limit))
+		return pagination, page.Offset((req.Page - 1) * req.Limit).Limit(req.Limit).Find(dest).Error
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, req.Sort)
+		if err != nil {
+			return nil, err
+		}
+		op := "<"
+		if req.Asc {
+			op = ">"
+		}
+		page = page.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", req.Sort.Expr, op, req.Sort.Expr, op),
+			after.value, after.value, after.id)
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	if err := page.Limit(req.Limit + 1).Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	if rows.Len() <= req.Limit {
+		return pagination, nil
+	}
+	rows.Set(rows.Slice(0, req.Limit))
+
+	lastID := uint(rows.Index(req.Limit - 1).FieldByName("ID").Uint())
+	model := reflect.New(rows.Type().Elem()).Interface()
+	next, err := encodeCursor(query, model, req.Sort, lastID)
+	if err != nil {
+		return nil, err
+	}
+	pagination.NextCursor = next
+	return pagination, nil
+}
+
+// encodeCursor reads the sort value of the last row on a page and packs it into a cursor
+func encodeCursor(query *gorm.DB, model interface{}, sort SortKey, lastID uint) (string, error) {
+	var value interface{}
+	row := query.Session(&gorm.Session{NewDB: true}).
+		Model(model).
+		Select(sort.Expr).
+		Where("id = ?", lastID).
+		Row()
+	if err := row.Scan(&value); err != nil {
+		return "", err
+	}
+
+	cur := cursor{Sort: sort.Name, ID: lastID}
+	switch v := value.(type) {
+	case time.Time:
+		cur.Value = v.Format(time.RFC3339Nano)
+	case []byte:
+		cur.Value = string(v)
+	case nil:
+	default:
+		cur.Value = fmt.Sprint(v)
+	}
+
+	raw, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+type decodedCursor struct {
+	value interface{}
+	id    uint
+}
+
+func decodeCursor(encoded string, sort SortKey) (decodedCursor, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", ErrInvalidPageRequest)
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return decodedCursor{}, invalid
+	}
+	var cur cursor
+	if err := json.Unmarshal(raw, &cur); err != nil || cur.Sort != sort.Name {
+		return decodedCursor{}, invalid
+	}
+
+	if sort.Time {
+		t, err := time.Parse(time.RFC3339Nano, cur.Value)
+		if err != nil {
+			return decodedCursor{}, invalid
+		}
+		return decodedCursor{value: t, id: cur.ID}, nil
+	}
+	return decodedCursor{value: cur.Value, id: cur.ID}, nil
+}
@@ -1,13 +1,21 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
 
 // Response represents a standard API response
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success    bool        `json:"success"`
+	Message    string      `json:"message,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Error      string      `json:"error,omitempty"`
 }
 
 // SuccessResponse sends a success response
@@ -19,6 +27,16 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 	})
 }
 
+// PaginatedResponse sends a success response with a pagination envelope
+func PaginatedResponse(c *gin.Context, statusCode int, message string, data interface{}, pagination *Pagination) {
+	c.JSON(statusCode, Response{
+		Success:    true,
+		Message:    message,
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
 // ErrorResponse sends an error response
 func ErrorResponse(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, Response{
@@ -26,3 +44,13 @@ func ErrorResponse(c *gin.Context, statusCode int, message string) {
 		Error:   message,
 	})
 }
+
+// TooManyRequestsResponse sends a 429 error response with a Retry-After header
+func TooManyRequestsResponse(c *gin.Context, retryAfter time.Duration, message string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	ErrorResponse(c, http.StatusTooManyRequests, message)
+}
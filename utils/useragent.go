@@ -0,0 +1,51 @@
+package utils
+
+import "strings"
+
+// browserPatterns and osPatterns map User-Agent substrings to display names; order
+// matters because many browsers include the tokens of the ones they derive from
+var (
+	browserPatterns = [][2]string{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+	}
+	osPatterns = [][2]string{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"},
+		{"Windows", "Windows"},
+		{"Linux", "Linux"},
+	}
+)
+
+// DescribeDevice returns a short human readable device description such as "Chrome on macOS"
+func DescribeDevice(userAgent string) string {
+	browser := matchPattern(userAgent, browserPatterns)
+	os := matchPattern(userAgent, osPatterns)
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Unknown device"
+	}
+}
+
+func matchPattern(userAgent string, patterns [][2]string) string {
+	for _, p := range patterns {
+		if strings.Contains(userAgent, p[0]) {
+			return p[1]
+		}
+	}
+	return ""
+}